@@ -0,0 +1,631 @@
+// Package analyzer defines an analysis pass reporting mistakes in the use of
+// a container that compile fine: references to services that are never
+// registered, type assertions on services of another type and method calls
+// on services that do not have the method.
+//
+// Services are only known when registered with a constant identifier in the
+// analysed package or in the packages it imports. Unknown identifiers are
+// therefore only reported for containers created by the function using them,
+// and not in packages that also load services from files or register them
+// under computed identifiers.
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	cargoPath      = "github.com/drgomesp/cargo"
+	containerPath  = cargoPath + "/container"
+	definitionPath = cargoPath + "/definition"
+	loaderPath     = cargoPath + "/loader"
+	methodPath     = cargoPath + "/method"
+	referencePath  = cargoPath + "/reference"
+)
+
+// Analyzer checks the identifiers, types and methods of the services used
+// with the cargo packages
+var Analyzer = &analysis.Analyzer{
+	Name:      "cargo",
+	Doc:       "check references to services, type assertions on services and method calls registered on them",
+	URL:       "https://godoc.org/github.com/drgomesp/cargo/analyzer",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(Services)},
+	Run:       run,
+}
+
+// Services registered by a package and the packages it imports, as known to
+// the packages importing it
+type Services struct {
+	IDs     []string
+	Dynamic bool
+}
+
+// AFact marks Services as a fact
+func (*Services) AFact() {}
+
+func (s *Services) String() string {
+	if s.Dynamic {
+		return "dynamic services"
+	}
+
+	return "services " + strings.Join(s.IDs, ", ")
+}
+
+// registry of the services known to a pass. Identifiers are compared in
+// lower case, as under the default identifier policy.
+type registry struct {
+	ids     map[string]string
+	types   map[string]types.Type
+	aliases map[string]string
+	dynamic bool
+	// created holds the local variables of the containers created by the
+	// package, and creators the declarations of the functions creating them
+	created  map[types.Object]bool
+	creators map[ast.Node]bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	r := &registry{
+		ids:      make(map[string]string),
+		types:    make(map[string]types.Type),
+		aliases:  make(map[string]string),
+		created:  make(map[types.Object]bool),
+		creators: make(map[ast.Node]bool),
+	}
+
+	for _, imported := range pass.Pkg.Imports() {
+		var fact Services
+
+		if pass.ImportPackageFact(imported, &fact) {
+			r.dynamic = r.dynamic || fact.Dynamic
+
+			for _, id := range fact.IDs {
+				r.add(id, nil)
+			}
+		}
+	}
+
+	definitions := make(map[types.Object]string)
+	calls := []ast.Node{(*ast.CallExpr)(nil), (*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+
+	inspect.WithStack(calls, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if isFunc(callee(pass, n), containerPath, "New") {
+				r.creators[stack[1]] = true
+			}
+
+			r.register(pass, n)
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 && len(n.Lhs) > 0 {
+				define(pass, definitions, n.Lhs[0], n.Rhs[0])
+				r.create(pass, n.Lhs[0], n.Rhs[0])
+			}
+		case *ast.ValueSpec:
+			if len(n.Values) == 1 && len(n.Names) > 0 {
+				define(pass, definitions, n.Names[0], n.Values[0])
+				r.create(pass, n.Names[0], n.Values[0])
+			}
+		}
+
+		return true
+	})
+
+	r.resolveAliases()
+
+	checks := []ast.Node{(*ast.CallExpr)(nil), (*ast.TypeAssertExpr)(nil)}
+
+	inspect.WithStack(checks, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			r.checkCall(pass, definitions, n, stack[1])
+		case *ast.TypeAssertExpr:
+			r.checkAssertion(pass, n)
+		}
+
+		return true
+	})
+
+	fact := &Services{Dynamic: r.dynamic}
+
+	for _, id := range r.ids {
+		fact.IDs = append(fact.IDs, id)
+	}
+
+	if len(fact.IDs) > 0 || fact.Dynamic {
+		sort.Strings(fact.IDs)
+		pass.ExportPackageFact(fact)
+	}
+
+	return nil, nil
+}
+
+// register records the service a call registers, if any
+func (r *registry) register(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := callee(pass, call)
+
+	switch {
+	case isMethod(fn, "Register", "Set"):
+		id, ok := stringValue(pass, call.Args[0])
+
+		if !ok {
+			r.dynamic = true
+			return
+		}
+
+		r.add(id, serviceType(pass, fn.Name(), call.Args[1]))
+	case isMethod(fn, "Alias"):
+		alias, ok := stringValue(pass, call.Args[0])
+
+		if !ok {
+			r.dynamic = true
+			return
+		}
+
+		r.add(alias, nil)
+
+		if id, ok := stringValue(pass, call.Args[1]); ok {
+			r.aliases[strings.ToLower(alias)] = id
+		}
+	case isFunc(fn, cargoPath, "Provide"):
+		if t := typeArgument(pass, call); t != nil {
+			r.add(typeID(t), t)
+		}
+	case isMethodOf(fn, loaderPath, "Loader", []string{"Load", "LoadFile", "Apply"}):
+		r.dynamic = true
+	}
+}
+
+func (r *registry) add(id string, t types.Type) {
+	key := strings.ToLower(id)
+	r.ids[key] = id
+
+	if t != nil {
+		r.types[key] = t
+	}
+}
+
+// create records lhs as holding a container if it is a local variable
+// assigned a new container
+func (r *registry) create(pass *analysis.Pass, lhs, rhs ast.Expr) {
+	ident, ok := lhs.(*ast.Ident)
+	call, isCall := ast.Unparen(rhs).(*ast.CallExpr)
+
+	if !ok || !isCall || !isFunc(callee(pass, call), containerPath, "New") {
+		return
+	}
+
+	if obj := pass.TypesInfo.ObjectOf(ident); obj != nil && obj.Parent() != pass.Pkg.Scope() {
+		r.created[obj] = true
+	}
+}
+
+// owns reports whether expr is a container created by the function using
+// it, whose services are all registered where the analysis sees them
+func (r *registry) owns(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && r.created[pass.TypesInfo.Uses[ident]]
+}
+
+// resolveAliases gives aliases the type of the services they refer to
+func (r *registry) resolveAliases() {
+	for alias := range r.aliases {
+		id, seen := alias, map[string]bool{}
+
+		for r.aliases[id] != "" && !seen[id] {
+			seen[id] = true
+			id = strings.ToLower(r.aliases[id])
+		}
+
+		if t, ok := r.types[id]; ok {
+			r.types[alias] = t
+		}
+	}
+}
+
+// known reports whether id refers to a registered service, which cannot be
+// told when services are registered dynamically
+func (r *registry) known(id string) bool {
+	if r.dynamic || len(r.ids) == 0 {
+		return true
+	}
+
+	_, ok := r.ids[strings.ToLower(id)]
+	return ok
+}
+
+// typeOf returns the type of the service id, or nil when it is unknown
+func (r *registry) typeOf(id string) types.Type {
+	return r.types[strings.ToLower(id)]
+}
+
+// checkCall reports unknown identifiers given to a call within the
+// declaration decl, services of the wrong type requested from the cargo
+// package and method calls registered on services that do not have the method
+func (r *registry) checkCall(pass *analysis.Pass, definitions map[types.Object]string, call *ast.CallExpr, decl ast.Node) {
+	fn := callee(pass, call)
+
+	switch {
+	case isMethod(fn, "Get", "MustGet", "Definition", "Decorate"):
+		r.checkID(pass, call.Args[0], r.owns(pass, receiver(call)))
+	case isMethod(fn, "GetContext", "Alias", "Bind"):
+		r.checkID(pass, call.Args[1], r.owns(pass, receiver(call)))
+	case isFunc(fn, referencePath, "New"):
+		r.checkID(pass, call.Args[0], r.creators[decl])
+	case isFunc(fn, cargoPath, "Get", "MustGet", "Bind"):
+		if id, ok := r.checkID(pass, call.Args[1], r.owns(pass, call.Args[0])); ok {
+			r.checkType(pass, call, id, typeArgument(pass, call))
+		}
+	case isDefinitionMethod(fn, "AddMethodCall"):
+		r.checkMethodCall(pass, definitions, call)
+	}
+}
+
+// checkID reports arg if it is the identifier of an unknown service, when
+// every service it may refer to is known
+func (r *registry) checkID(pass *analysis.Pass, arg ast.Expr, owned bool) (id string, ok bool) {
+	if id, ok = stringValue(pass, arg); !ok {
+		return
+	}
+
+	if owned && !r.known(id) {
+		pass.Reportf(arg.Pos(), `No service "%s" was found%s`, id, r.suggest(id))
+		return id, false
+	}
+
+	return
+}
+
+// suggest a registered identifier close to id
+func (r *registry) suggest(id string) string {
+	best, distance := "", 3
+
+	for key, candidate := range r.ids {
+		if d := levenshtein(strings.ToLower(id), key); d < distance || (d == distance && candidate < best) {
+			best, distance = candidate, d
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return ` (did you mean "` + best + `"?)`
+}
+
+// checkAssertion reports type assertions on services returned by MustGet
+// that cannot succeed
+func (r *registry) checkAssertion(pass *analysis.Pass, assertion *ast.TypeAssertExpr) {
+	call, ok := ast.Unparen(assertion.X).(*ast.CallExpr)
+
+	if !ok || assertion.Type == nil || !isMethod(callee(pass, call), "MustGet") {
+		return
+	}
+
+	if id, ok := stringValue(pass, call.Args[0]); ok && r.known(id) {
+		r.checkType(pass, assertion.Type, id, pass.TypesInfo.TypeOf(assertion.Type))
+	}
+}
+
+// checkType reports node if the service id cannot be of type t
+func (r *registry) checkType(pass *analysis.Pass, node ast.Node, id string, t types.Type) {
+	s := r.typeOf(id)
+
+	if s == nil || t == nil || assertable(s, t) {
+		return
+	}
+
+	pass.Reportf(node.Pos(), `Service "%s" is of type %s, not %s`, id, typeString(pass, s), typeString(pass, t))
+}
+
+// checkMethodCall reports a method call added to the definition of a service
+// whose type does not have the method or whose method expects other
+// arguments
+func (r *registry) checkMethodCall(pass *analysis.Pass, definitions map[types.Object]string, call *ast.CallExpr) {
+	m, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr)
+
+	if !ok || !isFunc(callee(pass, m), methodPath, "New") {
+		return
+	}
+
+	name, ok := stringValue(pass, m.Args[0])
+	id := definitionOf(pass, definitions, call)
+	t := r.typeOf(id)
+
+	if !ok || t == nil {
+		return
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, name)
+	fn, found := obj.(*types.Func)
+
+	if !found || !fn.Exported() {
+		pass.Reportf(m.Args[0].Pos(), `Method "%s" does not exist on %s`, name, typeString(pass, t))
+		return
+	}
+
+	if m.Ellipsis.IsValid() {
+		return
+	}
+
+	sig := fn.Type().(*types.Signature)
+	numArgs := len(m.Args) - 1
+
+	if numArgs != sig.Params().Len() && !(sig.Variadic() && numArgs >= sig.Params().Len()-1) {
+		pass.Reportf(m.Pos(), `Method "%s" expects %d arguments`, name, sig.Params().Len())
+	}
+}
+
+// define records the service a variable holds the definition of, when it is
+// assigned the result of a registration
+func define(pass *analysis.Pass, definitions map[types.Object]string, lhs, rhs ast.Expr) {
+	ident, ok := lhs.(*ast.Ident)
+	call, isCall := ast.Unparen(rhs).(*ast.CallExpr)
+
+	if !ok || !isCall || !isMethod(callee(pass, call), "Register") {
+		return
+	}
+
+	obj := pass.TypesInfo.ObjectOf(ident)
+	id, isConstant := stringValue(pass, call.Args[0])
+
+	if obj == nil {
+		return
+	}
+
+	if previous, defined := definitions[obj]; !isConstant || (defined && !strings.EqualFold(previous, id)) {
+		id = ""
+	}
+
+	definitions[obj] = id
+}
+
+// definitionOf returns the service whose definition a method of the
+// definition package is called on, following chained calls
+func definitionOf(pass *analysis.Pass, definitions map[types.Object]string, call *ast.CallExpr) string {
+	selector, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+
+	if !ok {
+		return ""
+	}
+
+	switch x := ast.Unparen(selector.X).(type) {
+	case *ast.Ident:
+		return definitions[pass.TypesInfo.Uses[x]]
+	case *ast.CallExpr:
+		if fn := callee(pass, x); fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == definitionPath {
+			return definitionOf(pass, definitions, x)
+		}
+	}
+
+	return ""
+}
+
+// serviceType returns the type of the service registered by a call to the
+// method name of a container with arg
+func serviceType(pass *analysis.Pass, name string, arg ast.Expr) types.Type {
+	t := pass.TypesInfo.TypeOf(arg)
+
+	if t == nil || types.Identical(t, types.Typ[types.UntypedNil]) {
+		return nil
+	}
+
+	if sig, ok := t.Underlying().(*types.Signature); ok && name == "Register" {
+		if sig.Results().Len() == 0 {
+			return nil
+		}
+
+		return sig.Results().At(0).Type()
+	}
+
+	if types.IsInterface(t) {
+		return nil
+	}
+
+	return t
+}
+
+// assertable reports whether a service of type s may be asserted to type t
+func assertable(s, t types.Type) bool {
+	if _, ok := t.(*types.TypeParam); ok {
+		return true
+	}
+
+	switch {
+	case types.IsInterface(s) && types.IsInterface(t):
+		return true
+	case types.IsInterface(s):
+		return types.Implements(t, s.Underlying().(*types.Interface))
+	case types.IsInterface(t):
+		return types.Implements(s, t.Underlying().(*types.Interface))
+	default:
+		return types.Identical(s, t)
+	}
+}
+
+// typeID returns the identifier cargo.ID gives to services of type t
+func typeID(t types.Type) string {
+	prefix := ""
+
+	for {
+		ptr, ok := t.(*types.Pointer)
+
+		if !ok {
+			break
+		}
+
+		prefix += "*"
+		t = ptr.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return prefix + named.Obj().Pkg().Path() + "." + named.Obj().Name()
+	}
+
+	return prefix + types.TypeString(t, nil)
+}
+
+// typeString formats t with the names of the packages other than the
+// analysed one
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+
+		return pkg.Name()
+	})
+}
+
+// receiver of a method call
+func receiver(call *ast.CallExpr) ast.Expr {
+	return ast.Unparen(call.Fun).(*ast.SelectorExpr).X
+}
+
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+
+	if fn != nil {
+		fn = fn.Origin()
+	}
+
+	return fn
+}
+
+// isMethod reports whether fn is one of the methods names of a container
+func isMethod(fn *types.Func, names ...string) bool {
+	return isMethodOf(fn, containerPath, "Container", names)
+}
+
+// isDefinitionMethod reports whether fn is one of the methods names of a
+// definition
+func isDefinitionMethod(fn *types.Func, names ...string) bool {
+	return isMethodOf(fn, definitionPath, "Interface", names) || isMethodOf(fn, definitionPath, "Definition", names)
+}
+
+func isMethodOf(fn *types.Func, path, typeName string, names []string) bool {
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != path || !contains(names, fn.Name()) {
+		return false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+
+	if recv == nil {
+		return false
+	}
+
+	t := recv.Type()
+
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == typeName
+}
+
+// isFunc reports whether fn is one of the functions names of the package path
+func isFunc(fn *types.Func, path string, names ...string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == path &&
+		fn.Type().(*types.Signature).Recv() == nil && contains(names, fn.Name())
+}
+
+// typeArgument returns the first type argument of a call to a generic
+// function
+func typeArgument(pass *analysis.Pass, call *ast.CallExpr) types.Type {
+	fun := ast.Unparen(call.Fun)
+
+	switch x := fun.(type) {
+	case *ast.IndexExpr:
+		fun = x.X
+	case *ast.IndexListExpr:
+		fun = x.X
+	}
+
+	var ident *ast.Ident
+
+	switch x := fun.(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	}
+
+	if ident == nil {
+		return nil
+	}
+
+	if instance, ok := pass.TypesInfo.Instances[ident]; ok && instance.TypeArgs.Len() > 0 {
+		return instance.TypeArgs.At(0)
+	}
+
+	return nil
+}
+
+// stringValue returns the value of a constant string expression
+func stringValue(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// levenshtein distance between a and b
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,74 @@
+package a // want package:"services \\*a.Cache, english, greeter, logger, welcome"
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/drgomesp/cargo"
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/method"
+	"github.com/drgomesp/cargo/reference"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (e *English) Greet() string { return "Hello" }
+
+type Logger struct{}
+
+func (l *Logger) SetLevel(level string) {}
+
+func (l *Logger) Printf(format string, args ...interface{}) {}
+
+func (l *Logger) flush() {}
+
+func NewLogger() *Logger { return &Logger{} }
+
+type Welcome struct{}
+
+func NewWelcome(greeter Greeter, logger *Logger) (*Welcome, error) { return &Welcome{}, nil }
+
+type Cache struct{}
+
+func NewCache() *Cache { return &Cache{} }
+
+func wire(ctx context.Context) {
+	c := container.New()
+	c.Set("english", &English{})
+	c.Alias("greeter", "english")
+
+	def, _ := c.Register("logger", NewLogger)
+	def.AddMethodCall(method.New("SetLevel", "debug"))
+	def.AddMethodCall(method.New("SetLevel")) // want `Method "SetLevel" expects 1 arguments`
+	def.AddMethodCall(method.New("Printf", "%s", "a", "b"))
+	def.AddMethodCall(method.New("SetLevl", "debug")) // want `Method "SetLevl" does not exist on \*Logger`
+	def.AddMethodCall(method.New("flush"))            // want `Method "flush" does not exist on \*Logger`
+
+	welcome, _ := c.Register("welcome", NewWelcome)
+	welcome.AddArguments(reference.New("greeter"), reference.New("logr")) // want `No service "logr" was found \(did you mean "logger"\?\)`
+	welcome.AddArguments().AddMethodCall(method.New("Greet"))             // want `Method "Greet" does not exist on \*Welcome`
+
+	cargo.Provide[*Cache](c, NewCache)
+
+	_ = c.MustGet("logger").(*Logger)
+	_ = c.MustGet("LOGGER").(interface{ SetLevel(string) })
+	_ = c.MustGet("logger").(*English) // want `Service "logger" is of type \*Logger, not \*English`
+	_ = c.MustGet("greeter").(Greeter)
+	_ = c.MustGet("greeter").(*Logger) // want `Service "greeter" is of type \*English, not \*Logger`
+	_ = c.MustGet("*a.Cache").(*Cache)
+	_, _ = c.Get("missing")            // want `No service "missing" was found`
+	_, _ = c.GetContext(ctx, "welcom") // want `No service "welcom" was found \(did you mean "welcome"\?\)`
+
+	_, _ = cargo.Get[*Welcome](c, "welcome")
+	_, _ = cargo.Get[Greeter](c, "english")
+	_ = cargo.MustGet[*English](c, "welcome")                    // want `Service "welcome" is of type \*Welcome, not \*English`
+	_ = cargo.Bind[Greeter](c, "logger")                         // want `Service "logger" is of type \*Logger, not Greeter`
+	_ = c.Bind(reflect.TypeOf((*Greeter)(nil)).Elem(), "englsh") // want `No service "englsh" was found \(did you mean "english"\?\)`
+
+	id := "any"
+	_, _ = c.Get(id)
+}
@@ -0,0 +1,16 @@
+package app // want package:"services handler, logger"
+
+import (
+	"github.com/drgomesp/cargo/container"
+
+	"lib"
+)
+
+func main() {
+	c := container.New()
+	c.Set("logger", &lib.Logger{})
+	lib.Install(c)
+
+	_ = c.MustGet("handler").(*lib.Handler)
+	_ = c.MustGet("handlr") // want `No service "handlr" was found \(did you mean "handler"\?\)`
+}
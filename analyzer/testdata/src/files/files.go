@@ -0,0 +1,17 @@
+package files // want package:"dynamic services"
+
+import (
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/loader"
+)
+
+type Logger struct{}
+
+func load(l *loader.Loader) {
+	c := container.New()
+	c.Set("logger", &Logger{})
+	l.LoadFile(c, "services.yml")
+
+	_ = c.MustGet("db")
+	_ = c.MustGet("logger").(*Logger)
+}
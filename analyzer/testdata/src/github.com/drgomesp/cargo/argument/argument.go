@@ -0,0 +1,7 @@
+// Package argument is a stub of the argument package for the tests of the
+// analyzer
+package argument
+
+type Interface interface {
+	Value() interface{}
+}
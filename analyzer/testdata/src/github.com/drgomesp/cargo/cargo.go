@@ -0,0 +1,17 @@
+// Package cargo is a stub of the cargo package for the tests of the analyzer
+package cargo
+
+import (
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+)
+
+func Get[T any](c *container.Container, id string) (service T, err error) { return }
+
+func MustGet[T any](c *container.Container, id string) (service T) { return }
+
+func Provide[T any](c *container.Container, constructor interface{}) (def definition.Interface, err error) {
+	return
+}
+
+func Bind[T any](c *container.Container, id string) error { return nil }
@@ -0,0 +1,36 @@
+// Package container is a stub of the container package for the tests of the
+// analyzer
+package container
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/drgomesp/cargo/definition"
+)
+
+type Container struct{}
+
+func New() *Container { return &Container{} }
+
+func (c *Container) Register(id string, arg interface{}) (def definition.Interface, err error) {
+	return
+}
+
+func (c *Container) Set(id string, arg interface{}) error { return nil }
+
+func (c *Container) Alias(alias, id string) error { return nil }
+
+func (c *Container) Bind(t reflect.Type, id string) error { return nil }
+
+func (c *Container) Get(id string) (interface{}, error) { return nil, nil }
+
+func (c *Container) GetContext(ctx context.Context, id string) (interface{}, error) { return nil, nil }
+
+func (c *Container) MustGet(id string) interface{} { return nil }
+
+func (c *Container) Definition(id string) (def definition.Interface, ok bool) { return }
+
+func (c *Container) Decorate(id string, fn interface{}, priority int) (def definition.Interface, err error) {
+	return
+}
@@ -0,0 +1,13 @@
+// Package definition is a stub of the definition package for the tests of
+// the analyzer
+package definition
+
+import (
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/method"
+)
+
+type Interface interface {
+	AddArguments(arg ...argument.Interface) Interface
+	AddMethodCall(method *method.Method) Interface
+}
@@ -0,0 +1,9 @@
+// Package loader is a stub of the loader package for the tests of the
+// analyzer
+package loader
+
+import "github.com/drgomesp/cargo/container"
+
+type Loader struct{}
+
+func (l *Loader) LoadFile(c *container.Container, path string) error { return nil }
@@ -0,0 +1,7 @@
+// Package method is a stub of the method package for the tests of the
+// analyzer
+package method
+
+type Method struct{}
+
+func New(name string, args ...interface{}) *Method { return &Method{} }
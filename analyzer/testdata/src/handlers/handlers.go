@@ -0,0 +1,7 @@
+package handlers // want package:"services logger"
+
+import "services"
+
+// Services of a container declared by another package may be registered by
+// any package importing it
+var logger = services.Container.MustGet("loger").(*services.Logger)
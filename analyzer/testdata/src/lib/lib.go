@@ -0,0 +1,16 @@
+package lib // want package:"services handler"
+
+import "github.com/drgomesp/cargo/container"
+
+type Logger struct{}
+
+type Handler struct{}
+
+func NewHandler() *Handler { return &Handler{} }
+
+// Install is given a container whose other services are registered by the
+// caller
+func Install(c *container.Container) {
+	c.Register("handler", NewHandler)
+	_ = c.MustGet("logger").(*Logger)
+}
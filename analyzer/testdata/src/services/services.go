@@ -0,0 +1,11 @@
+package services // want package:"services logger"
+
+import "github.com/drgomesp/cargo/container"
+
+type Logger struct{}
+
+var Container = container.New()
+
+func init() {
+	Container.Set("logger", &Logger{})
+}
@@ -0,0 +1,110 @@
+// Package cargo provides type-safe accessors on top of the service container
+package cargo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Get a service of type T
+func Get[T any](c *container.Container, id string) (service T, err error) {
+	t := typeOf[T]()
+
+	if def, ok := c.Definition(id); ok && !assignable(def.Type(), t) {
+		err = fmt.Errorf(`Service "%s" is of type %s, not %s`, id, def.Type(), t)
+		return
+	}
+
+	found, err := c.Get(id)
+
+	if err != nil {
+		return
+	}
+
+	if service, ok := found.(T); ok {
+		return service, nil
+	}
+
+	err = fmt.Errorf(`Service "%s" is of type %T, not %s`, id, found, t)
+	return
+}
+
+// MustGet is a wrapper for Get that panics if the service was not found or
+// is not of type T
+func MustGet[T any](c *container.Container, id string) T {
+	if service, err := Get[T](c, id); err != nil {
+		panic(err)
+	} else {
+		return service
+	}
+}
+
+// Resolve the only service whose type is T or, when T is an interface,
+// implements T
+func Resolve[T any](c *container.Container) (service T, err error) {
+	t := typeOf[T]()
+	found, err := c.GetByType(t)
+
+	if err != nil {
+		return
+	}
+
+	if service, ok := found.(T); ok {
+		return service, nil
+	}
+
+	err = fmt.Errorf("Service of type %T is not %s", found, t)
+	return
+}
+
+// Provide registers a constructor function for a service of type T under
+// the identifier returned by ID
+func Provide[T any](c *container.Container, constructor interface{}) (def definition.Interface, err error) {
+	t := typeOf[T]()
+
+	if def, err = definition.New(constructor); err != nil {
+		return
+	}
+
+	if !def.Type().AssignableTo(t) {
+		err = fmt.Errorf("Constructor function returns %s, not %s", def.Type(), t)
+		return
+	}
+
+	return c.Register(ID[T](), constructor)
+}
+
+// Bind the interface T to the service id
+func Bind[T any](c *container.Container, id string) error {
+	return c.Bind(typeOf[T](), id)
+}
+
+// ID of the services of type T registered with Provide
+func ID[T any]() string {
+	t := typeOf[T]()
+	prefix := ""
+
+	for t.Kind() == reflect.Ptr && t.Name() == "" {
+		prefix += "*"
+		t = t.Elem()
+	}
+
+	if t.PkgPath() == "" {
+		return prefix + t.String()
+	}
+
+	return prefix + t.PkgPath() + "." + t.Name()
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// assignable reports whether services defined with type from may be of type
+// to, which can only be known once built when from is an interface
+func assignable(from, to reflect.Type) bool {
+	return from.AssignableTo(to) || from.Kind() == reflect.Interface
+}
@@ -0,0 +1,146 @@
+package cargo
+
+import (
+	"testing"
+
+	"github.com/drgomesp/cargo/container"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (e *English) Greet() string {
+	return "hello"
+}
+
+type Client struct{}
+
+func TestGet(t *testing.T) {
+	Convey("Given a container with a client service", t, func() {
+		c := container.New()
+		client := &Client{}
+		c.Set("http.client", client)
+
+		Convey("When getting it with its own type", func() {
+			service, err := Get[*Client](c, "http.client")
+
+			Convey("Then it should return the typed service", func() {
+				So(err, ShouldBeNil)
+				So(service, ShouldPointTo, client)
+			})
+		})
+
+		Convey("When getting it with a type it is not assignable to", func() {
+			_, err := Get[*English](c, "http.client")
+
+			Convey("Then it should return a descriptive error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Service "http.client" is of type *cargo.Client, not *cargo.English`)
+			})
+
+			Convey("And MustGet should panic", func() {
+				So(func() { MustGet[*English](c, "http.client") }, ShouldPanic)
+			})
+		})
+
+		Convey("When getting a service that does not exist", func() {
+			_, err := Get[*Client](c, "missing")
+
+			Convey("Then it should return the container error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `No service "missing" was found`)
+			})
+		})
+	})
+}
+
+func TestGetWithInterfaceDefinition(t *testing.T) {
+	Convey("Given a service whose constructor returns an interface", t, func() {
+		c := container.New()
+		c.Register("greeter", func() Greeter { return &English{} })
+
+		Convey("When getting it with the concrete type it is built with", func() {
+			service, err := Get[*English](c, "greeter")
+
+			Convey("Then it should return the typed service", func() {
+				So(err, ShouldBeNil)
+				So(service.Greet(), ShouldEqual, "hello")
+			})
+		})
+
+		Convey("When getting it with a concrete type it is not built with", func() {
+			_, err := Get[*Client](c, "greeter")
+
+			Convey("Then it should return a descriptive error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Service "greeter" is of type *cargo.English, not *cargo.Client`)
+			})
+		})
+	})
+}
+
+func TestProvideAndResolve(t *testing.T) {
+	Convey("Given a container", t, func() {
+		c := container.New()
+
+		Convey("When a constructor is provided for an interface type", func() {
+			_, err := Provide[Greeter](c, func() *English { return &English{} })
+
+			Convey("Then it should be registered under the identifier of that type", func() {
+				So(err, ShouldBeNil)
+				So(ID[Greeter](), ShouldEqual, "github.com/drgomesp/cargo.Greeter")
+				So(MustGet[Greeter](c, ID[Greeter]()).Greet(), ShouldEqual, "hello")
+			})
+
+			Convey("And it should be resolved by type", func() {
+				greeter, err := Resolve[Greeter](c)
+
+				So(err, ShouldBeNil)
+				So(greeter.Greet(), ShouldEqual, "hello")
+			})
+		})
+
+		Convey("When a constructor is provided for a type it does not return", func() {
+			_, err := Provide[*Client](c, func() *English { return &English{} })
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Constructor function returns *cargo.English, not *cargo.Client")
+			})
+		})
+
+		Convey("When resolving a type no service provides", func() {
+			_, err := Resolve[*Client](c)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "No service of type *cargo.Client was found")
+			})
+		})
+	})
+}
+
+func TestBind(t *testing.T) {
+	Convey("Given a container with two greeters", t, func() {
+		c := container.New()
+		c.Register("english", &English{})
+		c.Register("formal", &English{})
+
+		Convey("When the greeter interface is bound to one of them", func() {
+			err := Bind[Greeter](c, "formal")
+
+			Convey("Then resolving the interface should return that service", func() {
+				So(err, ShouldBeNil)
+
+				greeter, err := Resolve[Greeter](c)
+
+				So(err, ShouldBeNil)
+				So(greeter, ShouldEqual, MustGet[Greeter](c, "formal"))
+			})
+		})
+	})
+}
@@ -0,0 +1,302 @@
+// Package cli implements the cargo command, which inspects configuration
+// files of services as loaded by the loader package
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/codegen"
+	"github.com/drgomesp/cargo/graph"
+	"github.com/drgomesp/cargo/loader"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+)
+
+const usage = `Usage: cargo [-f file] <command> [arguments]
+
+Commands:
+  list                 list the services with their type, scope and tags
+  describe <id>        describe a service, its arguments, calls and dependents
+  graph [flags]        print the dependency graph as DOT or Mermaid
+  validate             check the wiring, exiting with 1 on errors
+  generate [flags]     generate a container without reflection, which needs
+                       the constructors of a program calling cli.Run
+`
+
+// Run the cargo command with args, given without the program name, and
+// return its exit status. Constructors are looked up in registry, which may
+// be nil to inspect files without building a container, in which case types
+// are unknown and only the references between services are checked.
+func Run(args []string, stdout, stderr io.Writer, registry *loader.Registry) int {
+	flags := flag.NewFlagSet("cargo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+	}
+
+	path := flags.String("f", "services.yml", "configuration `file` to inspect")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cmd, args := flags.Arg(0), flags.Args()[1:]
+
+	switch cmd {
+	case "list", "describe", "graph", "validate", "generate":
+	default:
+		fmt.Fprintf(stderr, "Unknown command \"%s\"\n\n", cmd)
+		flags.Usage()
+		return 2
+	}
+
+	i, err := inspect(*path, registry)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	switch cmd {
+	case "list":
+		return list(i, stdout)
+	case "describe":
+		return describe(i, args, stdout, stderr)
+	case "graph":
+		return draw(i, args, stdout, stderr)
+	case "generate":
+		return generate(i, args, stdout, stderr)
+	default:
+		return validate(i, stdout, stderr)
+	}
+}
+
+func list(i *inspection, stdout io.Writer) int {
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCONSTRUCTOR\tTYPE\tSCOPE\tTAGS")
+
+	for _, node := range i.nodes {
+		service, _ := i.service(node.ID)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node.ID, orDash(service.Constructor), typeName(node.Type), node.Scope, orDash(tagNames(service.Tags)))
+	}
+
+	w.Flush()
+	return 0
+}
+
+func describe(i *inspection, args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "Usage: cargo describe <id>")
+		return 2
+	}
+
+	service, ok := i.service(args[0])
+
+	if !ok {
+		fmt.Fprintf(stderr, "No service \"%s\" was found\n", args[0])
+		return 1
+	}
+
+	node := i.node(service.ID)
+
+	var dependents []string
+
+	for _, other := range i.nodes {
+		for _, dep := range other.Dependencies {
+			if dep == node.ID {
+				dependents = append(dependents, other.ID)
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", service.ID)
+	fmt.Fprintf(w, "Type:\t%s\n", typeName(node.Type))
+	fmt.Fprintf(w, "Constructor:\t%s\n", service.Constructor)
+	fmt.Fprintf(w, "Scope:\t%s\n", node.Scope)
+	fmt.Fprintf(w, "Tags:\t%s\n", orDash(tagNames(service.Tags)))
+	fmt.Fprintf(w, "Arguments:\t%s\n", orDash(formatArguments(service.Arguments)))
+
+	for _, call := range service.Calls {
+		fmt.Fprintf(w, "Call:\t%s(%s)\n", call.Method, formatArguments(call.Arguments))
+	}
+
+	fmt.Fprintf(w, "Dependencies:\t%s\n", orDash(strings.Join(node.Dependencies, ", ")))
+	fmt.Fprintf(w, "Dependents:\t%s\n", orDash(strings.Join(dependents, ", ")))
+	w.Flush()
+
+	return 0
+}
+
+func draw(i *inspection, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	format := flags.String("format", "dot", "output `format`, dot or mermaid")
+	scopes := flags.Bool("scopes", false, "highlight prototype and scoped services")
+	unused := flags.Bool("unused", false, "highlight services no other service depends on")
+	root := flags.String("root", "", "only draw the service `id` and its dependencies")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var opts []graph.Option
+
+	if *scopes {
+		opts = append(opts, graph.WithScopes())
+	}
+
+	if *unused {
+		opts = append(opts, graph.WithUnused())
+	}
+
+	if *root != "" {
+		opts = append(opts, graph.WithRoot(*root))
+	}
+
+	var err error
+
+	switch *format {
+	case "dot":
+		err = graph.DOT(stdout, i, opts...)
+	case "mermaid":
+		err = graph.Mermaid(stdout, i, opts...)
+	default:
+		fmt.Fprintf(stderr, "Unknown format \"%s\"\n", *format)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func generate(i *inspection, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var opts codegen.Options
+
+	flags.StringVar(&opts.Package, "package", "main", "`name` of the package of the generated file")
+	flags.StringVar(&opts.PackagePath, "path", "", "import `path` of that package, unless it is main")
+	flags.StringVar(&opts.Type, "type", "Container", "`name` of the generated container type")
+	output := flags.String("o", "", "`file` to write, instead of the standard output")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if i.container == nil {
+		fmt.Fprintln(stderr, "Generating a container needs the constructors of a program calling cli.Run")
+		return 1
+	}
+
+	if err := i.err(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	var b bytes.Buffer
+
+	if err := codegen.Generate(&b, i.container, opts); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *output == "" {
+		stdout.Write(b.Bytes())
+		return 0
+	}
+
+	if err := os.WriteFile(*output, b.Bytes(), 0o644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func validate(i *inspection, stdout, stderr io.Writer) int {
+	if err := i.err(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "%s: %d services are valid\n", i.file.Name, len(i.nodes))
+	return 0
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func tagNames(tags []loader.Tag) string {
+	names := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		name := tag.Name
+
+		if len(tag.Attributes) > 0 {
+			attrs := make([]string, 0, len(tag.Attributes))
+
+			for key, value := range tag.Attributes {
+				attrs = append(attrs, fmt.Sprintf("%s=%v", key, value))
+			}
+
+			sort.Strings(attrs)
+			name += "(" + strings.Join(attrs, ", ") + ")"
+		}
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, " ")
+}
+
+func formatArguments(args []argument.Interface) string {
+	formatted := make([]string, len(args))
+
+	for n, arg := range args {
+		switch arg := arg.(type) {
+		case nil:
+			formatted[n] = "~"
+		case reference.Interface:
+			formatted[n] = "@" + arg.Identifier()
+		case tagged.Interface:
+			formatted[n] = "!tagged " + arg.Tag()
+		case parameter.Interface:
+			formatted[n] = fmt.Sprintf("%q", arg.Value())
+		default:
+			formatted[n] = fmt.Sprintf("%#v", arg.Value())
+		}
+	}
+
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drgomesp/cargo/loader"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Logger struct{}
+
+type DB struct{ Logger *Logger }
+
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+func NewDB(dsn string, logger *Logger) *DB {
+	return &DB{logger}
+}
+
+const services = `parameters:
+  db.host: localhost
+services:
+  logger:
+    constructor: NewLogger
+  db:
+    constructor: NewDB
+    arguments: ["postgres://%db.host%", "@logger"]
+    tags:
+      - name: health
+        priority: 10
+`
+
+const broken = `services:
+  db:
+    constructor: NewDB
+    arguments: ["postgres://%db.user%", "@log"]
+  a:
+    constructor: NewA
+    arguments: ["@b"]
+  b:
+    constructor: NewB
+    arguments: ["@a"]
+    scope: session
+  x:
+    constructor: NewX
+    arguments: ["@z"]
+  y:
+    constructor: NewY
+    arguments: ["@z"]
+  z:
+    constructor: NewZ
+    arguments: ["@y"]
+`
+
+func write(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "services.yml")
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func run(registry *loader.Registry, args ...string) (code int, stdout, stderr string) {
+	var out, errs bytes.Buffer
+	code = Run(args, &out, &errs, registry)
+
+	return code, out.String(), errs.String()
+}
+
+func TestRun(t *testing.T) {
+	path := write(t, services)
+	brokenPath := write(t, broken)
+
+	registry := loader.NewRegistry()
+	registry.Register("NewLogger", NewLogger)
+	registry.Register("NewDB", NewDB)
+
+	Convey("Given a configuration file", t, func() {
+		Convey("When listing its services", func() {
+			code, stdout, _ := run(nil, "-f", path, "list")
+
+			Convey("Then every service should be printed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldEqual, `ID      CONSTRUCTOR  TYPE  SCOPE   TAGS
+db      NewDB        -     shared  health(priority=10)
+logger  NewLogger    -     shared  -
+`)
+			})
+		})
+
+		Convey("When listing its services with a registry", func() {
+			code, stdout, _ := run(registry, "-f", path, "list")
+
+			Convey("Then their types should be printed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldContainSubstring, "db      NewDB        *cli.DB")
+			})
+		})
+
+		Convey("When describing a service", func() {
+			code, stdout, _ := run(registry, "-f", path, "describe", "logger")
+
+			Convey("Then its details and dependents should be printed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldEqual, `ID:            logger
+Type:          *cli.Logger
+Constructor:   NewLogger
+Scope:         shared
+Tags:          -
+Arguments:     -
+Dependencies:  -
+Dependents:    db
+`)
+			})
+		})
+
+		Convey("When describing a missing service", func() {
+			code, _, stderr := run(nil, "-f", path, "describe", "cache")
+
+			Convey("Then it should fail", func() {
+				So(code, ShouldEqual, 1)
+				So(stderr, ShouldEqual, "No service \"cache\" was found\n")
+			})
+		})
+
+		Convey("When drawing its graph", func() {
+			code, stdout, _ := run(nil, "-f", path, "graph", "-format", "mermaid")
+
+			Convey("Then the diagram should be printed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldContainSubstring, "n0 --> n1")
+			})
+		})
+
+		Convey("When validating it", func() {
+			code, stdout, _ := run(registry, "-f", path, "validate")
+
+			Convey("Then it should succeed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldEqual, path+": 2 services are valid\n")
+			})
+		})
+	})
+
+	Convey("Given a configuration file and its constructors", t, func() {
+		Convey("When generating its container", func() {
+			code, stdout, _ := run(registry, "-f", path, "generate", "-package", "cli", "-path", "github.com/drgomesp/cargo/cli")
+
+			Convey("Then the code should be printed", func() {
+				So(code, ShouldEqual, 0)
+				So(stdout, ShouldContainSubstring, `service = NewDB("postgres://localhost", v1)`)
+			})
+		})
+
+		Convey("When generating its container without constructors", func() {
+			code, _, stderr := run(nil, "-f", path, "generate")
+
+			Convey("Then it should fail", func() {
+				So(code, ShouldEqual, 1)
+				So(stderr, ShouldEqual, "Generating a container needs the constructors of a program calling cli.Run\n")
+			})
+		})
+	})
+
+	Convey("Given a configuration file with wiring errors", t, func() {
+		Convey("When validating it", func() {
+			code, _, stderr := run(nil, "-f", brokenPath, "validate")
+
+			Convey("Then every error should be reported", func() {
+				So(code, ShouldEqual, 1)
+				So(stderr, ShouldEqual, brokenPath+`:2: Service "db": No parameter "db.user" was found
+`+brokenPath+`:2: Service "db": No service "log" was found
+`+brokenPath+`:8: Service "b": Unknown scope "session"
+Circular dependency: a -> b -> a
+Circular dependency: y -> z -> y
+`)
+			})
+		})
+	})
+
+	Convey("Given an unknown command", t, func() {
+		code, _, stderr := run(nil, "-f", path, "deploy")
+
+		Convey("Then the usage should be printed", func() {
+			So(code, ShouldEqual, 2)
+			So(stderr, ShouldStartWith, "Unknown command \"deploy\"\n\nUsage: cargo")
+		})
+	})
+}
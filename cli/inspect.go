@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/loader"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+)
+
+// inspection of a configuration file, whose container is only built when
+// the constructors are known
+type inspection struct {
+	file      *loader.File
+	container *container.Container
+	nodes     []container.Node
+	errs      []error
+}
+
+// Nodes of the services of the file
+func (i *inspection) Nodes() []container.Node {
+	return i.nodes
+}
+
+// service of the file with the identifier id
+func (i *inspection) service(id string) (service loader.Service, ok bool) {
+	for _, service = range i.file.Services {
+		if strings.EqualFold(service.ID, id) {
+			return service, true
+		}
+	}
+
+	return
+}
+
+// node of the service with the identifier id
+func (i *inspection) node(id string) (node container.Node) {
+	for _, node = range i.nodes {
+		if node.ID == id {
+			return
+		}
+	}
+
+	return container.Node{ID: id}
+}
+
+// inspect the configuration file at path. The services are registered on a
+// container when registry is given, and only checked statically otherwise.
+func inspect(path string, registry *loader.Registry) (i *inspection, err error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	i = &inspection{}
+	file, err := loader.Parse(path, f)
+
+	if err != nil {
+		i.errs = append(i.errs, err)
+	}
+
+	i.file = file
+
+	if registry == nil {
+		i.static()
+		return i, nil
+	}
+
+	c := container.New()
+
+	if err := loader.New(registry).Apply(c, file); err != nil {
+		i.errs = append(i.errs, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		i.errs = append(i.errs, err)
+	}
+
+	i.container = c
+	i.nodes = c.Nodes()
+	return i, nil
+}
+
+// static builds the nodes of the file without any constructor, checking the
+// references to services and parameters and the cycles between services
+func (i *inspection) static() {
+	ids := make(map[string]string)
+	tags := make(map[string][]string)
+	params := make(map[string]bool)
+
+	for _, param := range i.file.Parameters {
+		params[param.Name] = true
+	}
+
+	for _, service := range i.file.Services {
+		ids[strings.ToLower(service.ID)] = service.ID
+
+		for _, tag := range service.Tags {
+			tags[tag.Name] = append(tags[tag.Name], service.ID)
+		}
+	}
+
+	for _, service := range i.file.Services {
+		fail := func(err error) {
+			i.errs = append(i.errs, &loader.Error{File: i.file.Name, Line: service.Line, Err: fmt.Errorf(`Service "%s": %s`, service.ID, err)})
+		}
+
+		node := container.Node{ID: service.ID}
+
+		if service.Scope != "" {
+			scope, err := definition.ParseScope(service.Scope)
+
+			if err != nil {
+				fail(err)
+			}
+
+			node.Scope = scope
+		}
+
+		for _, tag := range service.Tags {
+			node.Tags = append(node.Tags, definition.Tag{Name: tag.Name, Attributes: tag.Attributes})
+		}
+
+		args := service.Arguments
+
+		for _, call := range service.Calls {
+			args = append(args, call.Arguments...)
+		}
+
+		deps := make(map[string]bool)
+
+		for _, arg := range args {
+			switch arg := arg.(type) {
+			case reference.Interface:
+				if id, ok := ids[strings.ToLower(arg.Identifier())]; ok {
+					deps[id] = true
+				} else {
+					fail(fmt.Errorf(`No service "%s" was found`, arg.Identifier()))
+				}
+			case tagged.Interface:
+				for _, id := range tags[arg.Tag()] {
+					deps[id] = true
+				}
+			case parameter.Interface:
+				for _, name := range arg.Names() {
+					if !params[name] {
+						fail(fmt.Errorf(`No parameter "%s" was found`, name))
+					}
+				}
+			}
+		}
+
+		for id := range deps {
+			node.Dependencies = append(node.Dependencies, id)
+		}
+
+		sort.Strings(node.Dependencies)
+		i.nodes = append(i.nodes, node)
+	}
+
+	sort.Slice(i.nodes, func(a, b int) bool {
+		return i.nodes[a].ID < i.nodes[b].ID
+	})
+
+	i.errs = append(i.errs, i.cycles()...)
+}
+
+// cycles between the nodes, each reported once and in the same form as
+// Container.Validate reports them
+func (i *inspection) cycles() (errs []error) {
+	graph := make(map[string][]string, len(i.nodes))
+
+	for _, node := range i.nodes {
+		graph[node.ID] = node.Dependencies
+	}
+
+	for _, cycle := range container.Cycles(graph) {
+		errs = append(errs, &container.CircularDependencyError{Path: cycle})
+	}
+
+	return
+}
+
+// err joins the errors found in the file
+func (i *inspection) err() error {
+	return errors.Join(i.errs...)
+}
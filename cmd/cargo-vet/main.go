@@ -0,0 +1,17 @@
+// Command cargo-vet reports mistakes in the use of cargo containers that
+// compile fine, such as references to services that are never registered,
+// type assertions on services of another type and method calls on services
+// that do not have the method. It runs on its own or through go vet:
+//
+//	cargo-vet ./...
+//	go vet -vettool=$(which cargo-vet) ./...
+package main
+
+import (
+	"github.com/drgomesp/cargo/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
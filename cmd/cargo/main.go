@@ -0,0 +1,22 @@
+// Command cargo inspects a configuration file of services, as loaded by the
+// loader package:
+//
+//	cargo -f services.yml list
+//	cargo -f services.yml describe db
+//	cargo -f services.yml graph -format mermaid
+//	cargo -f services.yml validate
+//
+// Constructors are unknown to this command, so it checks the references
+// between services and parameters only. Programs calling cli.Run with the
+// registry of their constructors also report types and wiring errors.
+package main
+
+import (
+	"os"
+
+	"github.com/drgomesp/cargo/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr, nil))
+}
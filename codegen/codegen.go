@@ -0,0 +1,374 @@
+// Package codegen generates the Go code of a container building the services
+// of a cargo container with direct calls, without any reflection
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/parameter"
+)
+
+// Options of the generated code
+type Options struct {
+	// Package is the name of the package of the generated file
+	Package string
+	// PackagePath is the import path of that package, whose identifiers are
+	// left unqualified. The identifiers of a main package are always found
+	// under the path "main", which is then the path used.
+	PackagePath string
+	// Type is the name of the generated container type, Container by default
+	Type string
+}
+
+// Generate writes a Go file declaring a container type with a getter per
+// service of c, along with a constructor receiving the services set as
+// instances. Constructors, decorators and the types of services must be
+// package level and exported, scoped services are not supported, and literal
+// arguments must be of basic types. Environment variables are read when the
+// services are built, and container parameters are generated as literals.
+func Generate(w io.Writer, c *container.Container, opts Options) error {
+	if opts.Type == "" {
+		opts.Type = "Container"
+	}
+
+	if opts.Package == "main" {
+		opts.PackagePath = "main"
+	}
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	g := &generator{
+		opts:    opts,
+		c:       c,
+		imports: newImports(opts.PackagePath),
+		getters: make(map[string]string),
+	}
+
+	src, err := g.generate()
+
+	if err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(src)
+
+	if err != nil {
+		return fmt.Errorf("Generated code is invalid: %s", err)
+	}
+
+	_, err = w.Write(formatted)
+	return err
+}
+
+type generator struct {
+	opts    Options
+	c       *container.Container
+	imports *imports
+	nodes   []container.Node
+	getters map[string]string
+	types   map[string]string
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.nodes = g.c.Nodes()
+	g.types = make(map[string]string, len(g.nodes))
+	owners := make(map[string]string)
+
+	for _, node := range g.nodes {
+		if node.Scope == definition.Scoped {
+			return nil, fmt.Errorf(`Scoped service "%s" is not supported`, node.ID)
+		}
+
+		getter := exported(node.ID)
+
+		if other, ok := owners[getter]; ok {
+			return nil, fmt.Errorf(`Services "%s" and "%s" would both have a %s method`, other, node.ID, getter)
+		}
+
+		owners[getter] = node.ID
+		g.getters[node.ID] = getter
+
+		t, err := g.imports.typeExpr(node.Type)
+
+		if err != nil {
+			return nil, fmt.Errorf(`Service "%s": %s`, node.ID, err)
+		}
+
+		g.types[node.ID] = t
+	}
+
+	var methods strings.Builder
+
+	for _, node := range g.nodes {
+		if err := g.service(&methods, node); err != nil {
+			return nil, fmt.Errorf(`Service "%s": %s`, node.ID, err)
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString("// Code generated by cargo from container definitions. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.opts.Package)
+	g.imports.write(&b)
+
+	fmt.Fprintf(&b, "// %s builds the services with direct calls\n", g.opts.Type)
+	fmt.Fprintf(&b, "type %s struct {\n", g.opts.Type)
+
+	var params, fields []string
+
+	for _, node := range g.nodes {
+		field := unexported(g.getters[node.ID])
+
+		switch {
+		case node.External:
+			fmt.Fprintf(&b, "%s %s\n", field, g.types[node.ID])
+			params = append(params, field+" "+g.types[node.ID])
+			fields = append(fields, field+": "+field+",")
+		case node.Scope == definition.Shared:
+			fmt.Fprintf(&b, "%s struct {\nmu %sMutex\nbuilt bool\nservice %s\n}\n", field, g.imports.require("sync"), g.types[node.ID])
+		}
+	}
+
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// New%s creates a container holding the services set as instances\n", g.opts.Type)
+	fmt.Fprintf(&b, "func New%s(%s) *%s {\n", g.opts.Type, strings.Join(params, ", "), g.opts.Type)
+	fmt.Fprintf(&b, "return &%s{\n%s\n}\n}\n\n", g.opts.Type, strings.Join(fields, "\n"))
+
+	b.WriteString(methods.String())
+	return []byte(b.String()), nil
+}
+
+// service writes the getter of a service and the method building it
+func (g *generator) service(b *strings.Builder, node container.Node) error {
+	getter, t := g.getters[node.ID], g.types[node.ID]
+	field := unexported(getter)
+
+	fmt.Fprintf(b, "// %s returns the %q service\n", getter, node.ID)
+	fmt.Fprintf(b, "func (c *%s) %s() (%s, error) {\n", g.opts.Type, getter, t)
+
+	switch {
+	case node.External:
+		fmt.Fprintf(b, "return c.%s, nil\n}\n\n", field)
+		return nil
+	case node.Scope == definition.Shared:
+		g.imports.require("sync")
+		fmt.Fprintf(b, "c.%s.mu.Lock()\ndefer c.%s.mu.Unlock()\n\n", field, field)
+		fmt.Fprintf(b, "if !c.%s.built {\nservice, err := c.new%s()\n\nif err != nil {\nreturn service, err\n}\n\n", field, getter)
+		fmt.Fprintf(b, "c.%s.service, c.%s.built = service, true\n}\n\n", field, field)
+		fmt.Fprintf(b, "return c.%s.service, nil\n}\n\n", field)
+	default:
+		fmt.Fprintf(b, "return c.new%s()\n}\n\n", getter)
+	}
+
+	plan, err := g.c.Plan(node.ID)
+
+	if err != nil {
+		return err
+	}
+
+	body := &builder{generator: g}
+	def := plan.Definition
+
+	if constructor := def.Constructor(); constructor.IsValid() {
+		fn, err := g.imports.funcExpr(constructor)
+
+		if err != nil {
+			return err
+		}
+
+		args, err := body.arguments(plan.Arguments)
+
+		if err != nil {
+			return err
+		}
+
+		body.call("service", fn+"("+strings.Join(args, ", ")+")", constructor.Type().NumOut() > 1)
+	} else {
+		if !def.Template().IsNil() && !def.Template().Elem().IsZero() {
+			return fmt.Errorf("Struct templates with values are not supported")
+		}
+
+		elem, err := g.imports.typeExpr(def.Type().Elem())
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&body.code, "service = &%s{}\n", elem)
+	}
+
+	for _, field := range plan.Fields {
+		if !field.Field.IsExported() {
+			return fmt.Errorf(`Field "%s" is not exported`, field.Field.Name)
+		}
+
+		arg, err := body.argument(field.Wire)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&body.code, "service.%s = %s\n", field.Field.Name, arg)
+	}
+
+	for _, call := range plan.Calls {
+		args, err := body.arguments(call.Arguments)
+
+		if err != nil {
+			return err
+		}
+
+		expr := "service." + call.Method.Name + "(" + strings.Join(args, ", ") + ")"
+
+		if n := call.Type.NumOut(); n > 0 && call.Type.Out(n-1) == reflect.TypeOf((*error)(nil)).Elem() {
+			fmt.Fprintf(&body.code, "if %s%s; err != nil {\nreturn\n}\n", strings.Repeat("_, ", n-1)+"err = ", expr)
+		} else {
+			fmt.Fprintf(&body.code, "%s\n", expr)
+		}
+	}
+
+	for _, decorator := range plan.Decorators {
+		fn, err := g.imports.funcExpr(decorator.Definition.Constructor())
+
+		if err != nil {
+			return err
+		}
+
+		args, err := body.arguments(decorator.Arguments)
+
+		if err != nil {
+			return err
+		}
+
+		args = append([]string{"service"}, args...)
+		body.call("service", fn+"("+strings.Join(args, ", ")+")", decorator.Definition.Constructor().Type().NumOut() > 1)
+	}
+
+	fmt.Fprintf(b, "func (c *%s) new%s() (service %s, err error) {\n", g.opts.Type, getter, t)
+	b.WriteString(body.code.String())
+	b.WriteString("return\n}\n\n")
+
+	return nil
+}
+
+// builder of the body of a method building a service
+type builder struct {
+	*generator
+	code strings.Builder
+	vars int
+}
+
+// call writes the assignment of the result of a call to v, returning its
+// error, if any
+func (b *builder) call(v, expr string, fails bool) {
+	if !fails {
+		fmt.Fprintf(&b.code, "%s = %s\n", v, expr)
+		return
+	}
+
+	fmt.Fprintf(&b.code, "if %s, err = %s; err != nil {\nreturn\n}\n", v, expr)
+}
+
+func (b *builder) arguments(wires []container.Wire) (args []string, err error) {
+	args = make([]string, len(wires))
+
+	for i, wire := range wires {
+		if args[i], err = b.argument(wire); err != nil {
+			return nil, fmt.Errorf("Argument %d: %s", i, err)
+		}
+	}
+
+	return
+}
+
+// argument writes the statements resolving an argument and returns the
+// expression of its value
+func (b *builder) argument(wire container.Wire) (string, error) {
+	if param, ok := wire.Argument.(parameter.Interface); ok {
+		if expr, err := b.parameter(param, wire.Type); err != nil || expr != "" {
+			return expr, err
+		}
+	}
+
+	if len(wire.Services) == 0 && !wire.Tagged {
+		return b.imports.literal(wire.Value)
+	}
+
+	if !wire.Tagged {
+		return b.service(wire.Services[0], wire.Type)
+	}
+
+	t, err := b.imports.typeExpr(wire.Type)
+
+	if err != nil {
+		return "", err
+	}
+
+	v := b.variable()
+
+	if wire.Type.Kind() == reflect.Map {
+		fmt.Fprintf(&b.code, "%s := make(%s, %d)\n", v, t, len(wire.Services))
+	} else {
+		fmt.Fprintf(&b.code, "%s := make(%s, 0, %d)\n", v, t, len(wire.Services))
+	}
+
+	for _, id := range wire.Services {
+		service, err := b.service(id, wire.Type.Elem())
+
+		if err != nil {
+			return "", err
+		}
+
+		if wire.Type.Kind() == reflect.Map {
+			fmt.Fprintf(&b.code, "%s[%q] = %s\n", v, id, service)
+		} else {
+			fmt.Fprintf(&b.code, "%s = append(%s, %s)\n", v, v, service)
+		}
+	}
+
+	return v, nil
+}
+
+// service writes the statements getting the service id as a value of type
+// t and returns the variable holding it
+func (b *builder) service(id string, t reflect.Type) (string, error) {
+	getter, ok := b.getters[id]
+
+	if !ok {
+		return "", fmt.Errorf(`Service "%s" of a parent container is not supported`, id)
+	}
+
+	v := b.variable()
+	fmt.Fprintf(&b.code, "%s, err := c.%s()\nif err != nil {\nreturn\n}\n", v, getter)
+
+	for _, node := range b.nodes {
+		if node.ID == id && node.Type != nil && !node.Type.AssignableTo(t) {
+			expr, err := b.imports.typeExpr(t)
+
+			if err != nil {
+				return "", err
+			}
+
+			fmtPkg := b.imports.require("fmt")
+			asserted := b.variable()
+			fmt.Fprintf(&b.code, "%s, ok := %s.(%s)\nif !ok {\nerr = %sErrorf(\"Cannot use %%T as %s\", %s)\nreturn\n}\n", asserted, v, expr, fmtPkg, strings.ReplaceAll(expr, "%", "%%"), v)
+			return asserted, nil
+		}
+	}
+
+	return v, nil
+}
+
+func (b *builder) variable() string {
+	b.vars++
+	return fmt.Sprintf("v%d", b.vars)
+}
@@ -0,0 +1,283 @@
+package codegen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drgomesp/cargo/argument"
+	v1 "github.com/drgomesp/cargo/codegen/testdata/api/v1"
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/method"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (e *English) Greet() string {
+	return "hello"
+}
+
+type Loud struct{ Inner Greeter }
+
+func (l *Loud) Greet() string {
+	return strings.ToUpper(l.Inner.Greet())
+}
+
+type Handler struct {
+	Greeter  Greeter `inject:"greeter"`
+	Greeters []Greeter
+}
+
+type Welcome struct{ English *English }
+
+func NewWelcome(english *English) *Welcome {
+	return &Welcome{english}
+}
+
+func NewGreeter() (Greeter, error) {
+	return &English{}, nil
+}
+
+func NewLoud(inner Greeter) Greeter {
+	return &Loud{inner}
+}
+
+func (h *Handler) SetGreeters(greeters []Greeter) {
+	h.Greeters = greeters
+}
+
+// check type checks generated code importing standard packages only, along
+// with the other files of its package
+func check(src string, others ...string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "wiring.go", src, 0)
+
+	if err != nil {
+		return err
+	}
+
+	files := []*ast.File{file}
+
+	for _, other := range others {
+		if file, err = parser.ParseFile(fset, other, nil, 0); err != nil {
+			return err
+		}
+
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check(files[0].Name.Name, fset, files, nil)
+
+	return err
+}
+
+func TestGenerate(t *testing.T) {
+	Convey("Given a container of standard library services", t, func() {
+		c := container.New()
+		c.Set("out", &bytes.Buffer{})
+		c.SetParameter("log.flags", 3)
+
+		logger, _ := c.Register("logger", log.New)
+		logger.AddArguments(nil, argument.New("app: "), parameter.New("%log.flags%"))
+		logger.AddMethodCall(method.New("SetPrefix", "svc: "))
+
+		reader, _ := c.Register("reader", strings.NewReader)
+		reader.AddArguments(argument.New("hello"))
+		reader.SetScope(definition.Prototype)
+
+		timer, _ := c.Register("timer", time.NewTimer)
+		timer.AddArguments(argument.New(5 * time.Second))
+
+		Convey("When generating its code", func() {
+			var b bytes.Buffer
+			err := Generate(&b, c, Options{Package: "wiring", PackagePath: "example.com/wiring"})
+
+			Convey("Then it should be valid Go code building every service", func() {
+				So(err, ShouldBeNil)
+				So(check(b.String()), ShouldBeNil)
+
+				src := b.String()
+				So(src, ShouldStartWith, "// Code generated by cargo from container definitions. DO NOT EDIT.\n\npackage wiring\n")
+				So(src, ShouldContainSubstring, "func NewContainer(out *bytes.Buffer) *Container {")
+				So(src, ShouldContainSubstring, "func (c *Container) Logger() (*log.Logger, error) {")
+				So(src, ShouldContainSubstring, "if !c.logger.built {\n\t\tservice, err := c.newLogger()\n\n\t\tif err != nil {\n\t\t\treturn service, err\n\t\t}")
+				So(src, ShouldContainSubstring, "c.logger.service, c.logger.built = service, true")
+				So(src, ShouldContainSubstring, `service = log.New(v1, "app: ", 3)`)
+				So(src, ShouldContainSubstring, `service.SetPrefix("svc: ")`)
+				So(src, ShouldContainSubstring, "func (c *Container) Reader() (*strings.Reader, error) {\n\treturn c.newReader()\n}")
+				So(src, ShouldContainSubstring, "service = time.NewTimer(time.Duration(5000000000))")
+			})
+		})
+	})
+
+	Convey("Given a container with decorators, tags and injected fields", t, func() {
+		c := container.New()
+
+		greeter, _ := c.Register("greeter", NewGreeter)
+		greeter.AddTag("greeting", nil)
+		c.Decorate("greeter", NewLoud, 0)
+
+		handler, _ := c.Register("handler", &Handler{})
+		handler.AddMethodCall(method.New("SetGreeters", tagged.New("greeting")))
+
+		welcome, _ := c.Register("welcome", NewWelcome)
+		welcome.AddArguments(reference.New("greeter"))
+
+		Convey("When generating its code within the same package", func() {
+			var b bytes.Buffer
+			err := Generate(&b, c, Options{Package: "codegen", PackagePath: "github.com/drgomesp/cargo/codegen", Type: "Services"})
+
+			Convey("Then identifiers of the package should not be qualified", func() {
+				So(err, ShouldBeNil)
+
+				src := b.String()
+				So(src, ShouldContainSubstring, "func (c *Services) Greeter() (Greeter, error) {")
+				So(src, ShouldContainSubstring, "if service, err = NewGreeter(); err != nil {")
+				So(src, ShouldContainSubstring, "service = NewLoud(service)")
+				So(src, ShouldContainSubstring, "service = &Handler{}")
+				So(src, ShouldContainSubstring, "service.Greeter = v1")
+				So(src, ShouldContainSubstring, "v2 := make([]Greeter, 0, 1)")
+				So(src, ShouldContainSubstring, "service.SetGreeters(v2)")
+				So(src, ShouldContainSubstring, `v2, ok := v1.(*English)
+	if !ok {
+		err = fmt.Errorf("Cannot use %T as *English", v1)
+		return
+	}
+	service = NewWelcome(v2)`)
+			})
+		})
+	})
+
+	Convey("Given a container with an anonymous constructor", t, func() {
+		c := container.New()
+		c.Register("greeter", func() *English { return &English{} })
+
+		Convey("Then generating its code should return an error", func() {
+			err := Generate(&bytes.Buffer{}, c, Options{Package: "wiring"})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Service "greeter": Function github.com/drgomesp/cargo/codegen.TestGenerate.func3.1 is not a package level function`)
+		})
+	})
+
+	Convey("Given a container with a scoped service", t, func() {
+		c := container.New()
+		def, _ := c.Register("greeter", &English{})
+		def.SetScope(definition.Scoped)
+
+		Convey("Then generating its code should return an error", func() {
+			err := Generate(&bytes.Buffer{}, c, Options{Package: "wiring"})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Scoped service "greeter" is not supported`)
+		})
+	})
+
+	Convey("Given a container with invalid wiring", t, func() {
+		c := container.New()
+		def, _ := c.Register("logger", log.New)
+		def.AddArguments(reference.New("out"))
+
+		Convey("Then generating its code should return the validation errors", func() {
+			err := Generate(&bytes.Buffer{}, c, Options{Package: "wiring"})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, `Argument 0 of "logger": No service "out" was found`)
+		})
+	})
+
+	Convey("Given a container of services configured from the environment", t, func() {
+		c := container.New()
+		c.Set("out", &bytes.Buffer{})
+		c.SetParameter("app.name", "cargo")
+
+		logger, _ := c.Register("logger", log.New)
+		logger.AddArguments(nil, parameter.New("%app.name% (%env(APP_ENV=dev)%, %env(int:APP_WORKERS)%): "), parameter.Env("int:LOG_FLAGS=3"))
+
+		timer, _ := c.Register("timer", time.NewTimer)
+		timer.AddArguments(parameter.Env("duration:TIMEOUT=5s"))
+
+		Convey("When generating its code", func() {
+			var b bytes.Buffer
+			err := Generate(&b, c, Options{Package: "wiring", PackagePath: "example.com/wiring"})
+
+			Convey("Then the variables should be read when building the services", func() {
+				So(err, ShouldBeNil)
+				So(check(b.String()), ShouldBeNil)
+
+				src := b.String()
+				So(src, ShouldContainSubstring, `v2, ok := os.LookupEnv("APP_ENV")
+	if !ok {
+		v2 = "dev"
+	}`)
+				So(src, ShouldContainSubstring, `v3, ok := os.LookupEnv("APP_WORKERS")
+	if !ok {
+		err = errors.New("Environment variable \"APP_WORKERS\" is not set")
+		return
+	}
+	v4, err := strconv.Atoi(v3)
+	if err != nil {
+		err = fmt.Errorf("Environment variable \"APP_WORKERS\" is not a valid int: \"%s\"", v3)
+		return
+	}`)
+				So(src, ShouldContainSubstring, `v6, err := strconv.ParseInt(v5, 10, 64)`)
+				So(src, ShouldContainSubstring, `service = log.New(v1, "cargo ("+v2+", "+fmt.Sprint(v4)+"): ", int(v6))`)
+				So(src, ShouldContainSubstring, `v2, err := time.ParseDuration(v1)`)
+				So(src, ShouldContainSubstring, `service = time.NewTimer(v2)`)
+			})
+		})
+	})
+
+	Convey("Given a container of services from a package named like a variable", t, func() {
+		c := container.New()
+		c.Register("pod", v1.NewPod)
+		c.Register("a", v1.NewA)
+
+		Convey("When generating its code", func() {
+			var b bytes.Buffer
+			err := Generate(&b, c, Options{Package: "wiring", PackagePath: "example.com/wiring"})
+
+			Convey("Then the package should not be shadowed by the variables", func() {
+				So(err, ShouldBeNil)
+				So(check(b.String()), ShouldBeNil)
+				So(b.String(), ShouldContainSubstring, "service = v1pkg.NewA(v1)")
+			})
+		})
+	})
+}
+
+func TestGenerateMain(t *testing.T) {
+	Convey("Given a program registering constructors of its main package", t, func() {
+		Convey("When it generates its container", func() {
+			out, err := exec.Command("go", "run", "./testdata/program").Output()
+
+			Convey("Then the constructors should not be qualified", func() {
+				So(err, ShouldBeNil)
+				So(check(string(out), filepath.Join("testdata", "program", "services.go")), ShouldBeNil)
+
+				src := string(out)
+				So(src, ShouldNotContainSubstring, `"main"`)
+				So(src, ShouldContainSubstring, `if service, err = NewDB("postgres://localhost", v1); err != nil {`)
+			})
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drgomesp/cargo/parameter"
+)
+
+// parameter writes the statements reading the environment variables of a
+// parameter argument of type t, and returns the expression of its value, or
+// an empty string when it does not refer to any environment variable
+func (b *builder) parameter(param parameter.Interface, t reflect.Type) (string, error) {
+	parts, err := param.Parts()
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(parts) == 1 && parts[0].Env != nil {
+		return b.env(parts[0].Env, t)
+	}
+
+	var (
+		exprs   []string
+		literal strings.Builder
+		reads   bool
+	)
+
+	for _, part := range parts {
+		switch {
+		case !part.Placeholder:
+			literal.WriteString(part.Text)
+		case part.Env == nil:
+			value, err := b.c.GetParameter(part.Text)
+
+			if err != nil {
+				return "", err
+			}
+
+			fmt.Fprint(&literal, value)
+		default:
+			if literal.Len() > 0 {
+				exprs = append(exprs, strconv.Quote(literal.String()))
+				literal.Reset()
+			}
+
+			expr, err := b.env(part.Env, nil)
+
+			if err != nil {
+				return "", err
+			}
+
+			exprs, reads = append(exprs, expr), true
+		}
+	}
+
+	if !reads {
+		return "", nil
+	}
+
+	if literal.Len() > 0 {
+		exprs = append(exprs, strconv.Quote(literal.String()))
+	}
+
+	expr := strings.Join(exprs, " + ")
+
+	if t == reflect.TypeOf("") || t.Kind() == reflect.Interface {
+		return expr, nil
+	}
+
+	typ, err := b.imports.typeExpr(t)
+
+	if err != nil {
+		return "", err
+	}
+
+	return typ + "(" + expr + ")", nil
+}
+
+// env writes the statements reading the environment variable v and
+// converting it to a value of type t, and returns the variable holding it.
+// Without a type, the expression returned is the string form of the value.
+func (b *builder) env(v *parameter.Variable, t reflect.Type) (string, error) {
+	raw := b.variable()
+	fmt.Fprintf(&b.code, "%s, ok := %sLookupEnv(%q)\n", raw, b.imports.require("os"), v.Name)
+
+	if v.HasDefault {
+		fmt.Fprintf(&b.code, "if !ok {\n%s = %q\n}\n", raw, v.Default)
+	} else {
+		fmt.Fprintf(&b.code, "if !ok {\nerr = %sNew(%q)\nreturn\n}\n", b.imports.require("errors"), `Environment variable "`+v.Name+`" is not set`)
+	}
+
+	if v.Type == "string" {
+		if t == nil {
+			return raw, nil
+		}
+
+		return b.convert(raw, reflect.TypeOf(""), t, true)
+	}
+
+	var (
+		kind   reflect.Kind
+		parse  string
+		parsed reflect.Type
+		ranged bool
+	)
+
+	if t != nil {
+		kind = t.Kind()
+	}
+
+	switch v.Type {
+	case "int":
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			parse, parsed, ranged = fmt.Sprintf("%sParseInt(%s, 10, %d)", b.imports.require("strconv"), raw, t.Bits()), reflect.TypeOf(int64(0)), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			parse, parsed, ranged = fmt.Sprintf("%sParseUint(%s, 10, %d)", b.imports.require("strconv"), raw, t.Bits()), reflect.TypeOf(uint64(0)), true
+		default:
+			parse, parsed = fmt.Sprintf("%sAtoi(%s)", b.imports.require("strconv"), raw), reflect.TypeOf(0)
+		}
+	case "float":
+		bits := 64
+
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			bits, ranged = t.Bits(), true
+		}
+
+		parse, parsed = fmt.Sprintf("%sParseFloat(%s, %d)", b.imports.require("strconv"), raw, bits), reflect.TypeOf(0.0)
+	case "bool":
+		parse, parsed, ranged = fmt.Sprintf("%sParseBool(%s)", b.imports.require("strconv"), raw), reflect.TypeOf(false), true
+	case "duration":
+		parse, parsed = fmt.Sprintf("%sParseDuration(%s)", b.imports.require("time"), raw), reflect.TypeOf(time.Duration(0))
+	default:
+		return "", fmt.Errorf(`Environment variable "%s" has an unknown type "%s"`, v.Name, v.Type)
+	}
+
+	value := b.variable()
+	format := `Environment variable "` + strings.ReplaceAll(v.Name, "%", "%%") + `" is not a valid ` + v.Type + `: "%s"`
+	fmt.Fprintf(&b.code, "%s, err := %s\nif err != nil {\nerr = %sErrorf(%q, %s)\nreturn\n}\n", value, parse, b.imports.require("fmt"), format, raw)
+
+	if t == nil {
+		return b.imports.require("fmt") + "Sprint(" + value + ")", nil
+	}
+
+	return b.convert(value, parsed, t, ranged)
+}
+
+// convert writes the statements converting the variable v of type from to a
+// value of type t, and returns the expression of that value. Unless ranged
+// tells that v fits t, numbers are checked not to lose data.
+func (b *builder) convert(v string, from, t reflect.Type, ranged bool) (string, error) {
+	if from == t || t.Kind() == reflect.Interface {
+		return v, nil
+	}
+
+	typ, err := b.imports.typeExpr(t)
+
+	if err != nil {
+		return "", err
+	}
+
+	if ranged || from.Kind() == reflect.String {
+		return typ + "(" + v + ")", nil
+	}
+
+	src, err := b.imports.typeExpr(from)
+
+	if err != nil {
+		return "", err
+	}
+
+	converted := b.variable()
+	fmt.Fprintf(&b.code, "%s := %s(%s)\nif %s(%s) != %s {\nerr = %sNew(%q)\nreturn\n}\n", converted, typ, v, src, converted, v, b.imports.require("errors"), "Cannot use "+from.String()+" as "+t.String())
+
+	return converted, nil
+}
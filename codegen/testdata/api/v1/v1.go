@@ -0,0 +1,14 @@
+// Package v1 has the name of the variables of the generated code
+package v1
+
+type Pod struct{}
+
+type A struct{ Pod *Pod }
+
+func NewPod() *Pod {
+	return &Pod{}
+}
+
+func NewA(pod *Pod) *A {
+	return &A{pod}
+}
@@ -0,0 +1,25 @@
+// Command program prints the container generated from constructors of its
+// own main package, as a program calling cli.Run would
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/codegen"
+	"github.com/drgomesp/cargo/container"
+)
+
+func main() {
+	c := container.New()
+	c.Register("logger", NewLogger)
+
+	db, _ := c.Register("db", NewDB)
+	db.AddArguments(argument.New("postgres://localhost"))
+
+	if err := codegen.Generate(os.Stdout, c, codegen.Options{Package: "main"}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "log"
+
+type DB struct {
+	DSN    string
+	Logger *log.Logger
+}
+
+func NewDB(dsn string, logger *log.Logger) (*DB, error) {
+	return &DB{dsn, logger}, nil
+}
+
+func NewLogger() *log.Logger {
+	return log.Default()
+}
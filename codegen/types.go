@@ -0,0 +1,298 @@
+package codegen
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// imports of the generated file, by import path
+type imports struct {
+	self  string
+	names map[string]string
+	taken map[string]bool
+}
+
+func newImports(self string) *imports {
+	return &imports{
+		self:  self,
+		names: make(map[string]string),
+		taken: make(map[string]bool),
+	}
+}
+
+// qualify returns the prefix of the identifiers of the package at path,
+// where name is the preferred name of the package
+func (i *imports) qualify(pkg, name string) string {
+	if pkg == i.self {
+		return ""
+	}
+
+	if found, ok := i.names[pkg]; ok {
+		return found + "."
+	}
+
+	if name == "" {
+		name = sanitize(path.Base(pkg))
+	}
+
+	if local(name) {
+		name += "pkg"
+	}
+
+	unique := name
+
+	for n := 2; i.taken[unique] || token.IsKeyword(unique); n++ {
+		unique = name + strconv.Itoa(n)
+	}
+
+	i.names[pkg] = unique
+	i.taken[unique] = true
+
+	return unique + "."
+}
+
+// require the standard package at path, returning the prefix of its
+// identifiers
+func (i *imports) require(pkg string) string {
+	return i.qualify(pkg, path.Base(pkg))
+}
+
+// locals are the fixed names of the variables of the generated methods
+var locals = map[string]bool{"c": true, "service": true, "err": true, "ok": true}
+
+// local tells whether name is the name of a variable of the generated
+// methods, which a package of the same name would be shadowed by
+func local(name string) bool {
+	return locals[name] || len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == ""
+}
+
+// write the import declaration
+func (i *imports) write(b *strings.Builder) {
+	if len(i.names) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(i.names))
+
+	for pkg := range i.names {
+		paths = append(paths, pkg)
+	}
+
+	sort.Strings(paths)
+	b.WriteString("import (\n")
+
+	for _, pkg := range paths {
+		if name := i.names[pkg]; name != path.Base(pkg) {
+			fmt.Fprintf(b, "\t%s %q\n", name, pkg)
+		} else {
+			fmt.Fprintf(b, "\t%q\n", pkg)
+		}
+	}
+
+	b.WriteString(")\n\n")
+}
+
+// typeExpr returns the Go expression of the type t
+func (i *imports) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+
+		if strings.ContainsRune(t.Name(), '[') {
+			return "", fmt.Errorf("Generic type %s is not supported", t)
+		}
+
+		name := strings.TrimSuffix(t.String(), "."+t.Name())
+		return i.qualify(t.PkgPath(), name) + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		elem, err := i.typeExpr(t.Elem())
+
+		if err != nil {
+			return "", err
+		}
+
+		switch t.Kind() {
+		case reflect.Ptr:
+			return "*" + elem, nil
+		case reflect.Slice:
+			return "[]" + elem, nil
+		case reflect.Array:
+			return fmt.Sprintf("[%d]%s", t.Len(), elem), nil
+		}
+
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem, nil
+		case reflect.SendDir:
+			return "chan<- " + elem, nil
+		}
+
+		return "chan " + elem, nil
+	case reflect.Map:
+		key, err := i.typeExpr(t.Key())
+
+		if err != nil {
+			return "", err
+		}
+
+		elem, err := i.typeExpr(t.Elem())
+
+		if err != nil {
+			return "", err
+		}
+
+		return "map[" + key + "]" + elem, nil
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			return "struct{}", nil
+		}
+	}
+
+	return "", fmt.Errorf("Type %s is not supported", t)
+}
+
+// funcExpr returns the Go expression of the package level function fn
+func (i *imports) funcExpr(fn reflect.Value) (string, error) {
+	name := runtime.FuncForPC(fn.Pointer()).Name()
+	slash := strings.LastIndexByte(name, '/')
+	dot := slash + 1 + strings.IndexByte(name[slash+1:], '.')
+
+	if dot <= slash {
+		return "", fmt.Errorf("Function %s is not supported", name)
+	}
+
+	pkg, ident := name[:dot], name[dot+1:]
+
+	if !token.IsIdentifier(ident) {
+		return "", fmt.Errorf("Function %s is not a package level function", name)
+	}
+
+	if pkg != i.self && !token.IsExported(ident) {
+		return "", fmt.Errorf("Function %s is not exported", name)
+	}
+
+	return i.qualify(pkg, "") + ident, nil
+}
+
+// literal returns the Go expression of the value v
+func (i *imports) literal(v reflect.Value) (string, error) {
+	if !v.IsValid() {
+		return "nil", nil
+	}
+
+	var lit string
+
+	switch v.Kind() {
+	case reflect.Bool:
+		lit = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lit = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		lit = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+
+		if !strings.ContainsAny(lit, ".e") {
+			lit += ".0"
+		}
+
+		if strings.ContainsAny(lit, "IN") {
+			return "", fmt.Errorf("Value %s is not supported", lit)
+		}
+	case reflect.String:
+		lit = strconv.Quote(v.String())
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		if v.IsNil() {
+			return "nil", nil
+		}
+
+		fallthrough
+	default:
+		return "", fmt.Errorf("Values of type %s are not supported", v.Type())
+	}
+
+	switch v.Type() {
+	case reflect.TypeOf(false), reflect.TypeOf(0), reflect.TypeOf(0.0), reflect.TypeOf(""):
+		return lit, nil
+	}
+
+	t, err := i.typeExpr(v.Type())
+
+	if err != nil {
+		return "", err
+	}
+
+	return t + "(" + lit + ")", nil
+}
+
+// sanitize a package name into an identifier
+func sanitize(name string) string {
+	name = strings.TrimPrefix(name, "go-")
+
+	if dot := strings.IndexByte(name, '.'); dot > 0 {
+		name = name[:dot]
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+
+		return -1
+	}, name)
+
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "pkg" + name
+	}
+
+	return name
+}
+
+// exported turns a service identifier into an exported Go identifier
+func exported(id string) string {
+	var b strings.Builder
+
+	for _, part := range strings.FieldsFunc(id, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r, size := utf8.DecodeRuneInString(part)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(part[size:])
+	}
+
+	name := b.String()
+
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(r) {
+		name = "S" + name
+	}
+
+	return name
+}
+
+// unexported turns an exported Go identifier into an unexported one
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	name = string(unicode.ToLower(r)) + name[size:]
+
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+
+	return name
+}
@@ -0,0 +1,94 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Alias makes the service id available under another identifier as well,
+// both returning the same instance
+func (c *Container) Alias(alias, id string) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
+		return
+	}
+
+	if taken, ok := c.defined(alias); ok {
+		err = alreadyExists(alias, taken)
+		return
+	}
+
+	c.aliases[alias] = id
+	c.name(alias)
+	return
+}
+
+// Bind the interface t to the service id, which is then the one resolved by
+// type for t even when other services implement it
+func (c *Container) Bind(t reflect.Type, id string) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
+		return
+	}
+
+	if t == nil || t.Kind() != reflect.Interface {
+		err = fmt.Errorf("Cannot bind %s, which is not an interface", t)
+		return
+	}
+
+	if bound, ok := c.bindings[t]; ok {
+		err = fmt.Errorf(`%s is already bound to "%s"`, t, bound)
+		return
+	}
+
+	c.bindings[t] = id
+	return
+}
+
+// checkAliases reports aliases and bindings to missing services, and
+// bindings to services not implementing their interface. The caller must
+// hold the container lock.
+func (c *Container) checkAliases() (errs []error) {
+	aliases := make([]string, 0, len(c.aliases))
+
+	for alias := range c.aliases {
+		aliases = append(aliases, alias)
+	}
+
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		if _, _, ok := c.definition(alias); !ok {
+			errs = append(errs, fmt.Errorf(`Alias "%s" refers to missing service "%s"`, alias, c.aliases[alias]))
+		}
+	}
+
+	bindings := make([]reflect.Type, 0, len(c.bindings))
+
+	for t := range c.bindings {
+		bindings = append(bindings, t)
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].String() < bindings[j].String()
+	})
+
+	for _, t := range bindings {
+		id := c.bindings[t]
+		_, def, ok := c.definition(id)
+
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf(`Binding of %s refers to missing service "%s"`, t, id))
+		case def.Type() != nil && !def.Type().Implements(t):
+			errs = append(errs, fmt.Errorf(`Service "%s" bound to %s does not implement it`, id, t))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,162 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var greeterType = reflect.TypeOf((*Greeter)(nil)).Elem()
+
+func TestAlias(t *testing.T) {
+	Convey("Given a container with a service", t, func() {
+		container := New()
+		container.Register("english.greeter", &English{})
+
+		Convey("When an alias is added for it", func() {
+			err := container.Alias("greeter", "english.greeter")
+
+			Convey("Then getting the alias should return the same instance", func() {
+				So(err, ShouldBeNil)
+
+				greeter, err := container.Get("greeter")
+
+				So(err, ShouldBeNil)
+				So(greeter, ShouldEqual, container.MustGet("english.greeter"))
+				So(container.Validate(), ShouldBeNil)
+			})
+
+			Convey("And references to the alias should be resolved", func() {
+				def, _ := container.Register("welcome", NewWelcome)
+				def.AddArguments(reference.New("greeter"), argument.New(&Foo{}), argument.New(1))
+
+				welcome, err := container.Get("welcome")
+
+				So(err, ShouldBeNil)
+				So(welcome.(*Welcome).Greeter, ShouldEqual, container.MustGet("english.greeter"))
+			})
+
+			Convey("And a child container should resolve it as well", func() {
+				greeter, err := container.NewChild().Get("greeter")
+
+				So(err, ShouldBeNil)
+				So(greeter, ShouldEqual, container.MustGet("english.greeter"))
+			})
+		})
+
+		Convey("When an alias uses an identifier that is already taken", func() {
+			err := container.Alias("english.greeter", "greeter")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Definition for "english.greeter" already exists`)
+			})
+		})
+
+		Convey("When a service is registered under an alias", func() {
+			container.Alias("greeter", "english.greeter")
+			_, err := container.Register("greeter", &English{})
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Definition for "greeter" already exists`)
+			})
+		})
+
+		Convey("When aliases refer to missing services or to each other", func() {
+			container.Alias("spanish", "spanish.greeter")
+			container.Alias("a", "b")
+			container.Alias("b", "a")
+
+			Convey("Then getting them should return an error", func() {
+				_, err := container.Get("a")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `No service "a" was found`)
+			})
+
+			Convey("And validating the container should report them", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Alias "a" refers to missing service "b"
+Alias "b" refers to missing service "a"
+Alias "spanish" refers to missing service "spanish.greeter"`)
+			})
+		})
+	})
+}
+
+func TestBind(t *testing.T) {
+	Convey("Given a container with two services implementing an interface", t, func() {
+		container := New()
+		container.Register("english", &English{})
+		container.Register("formal", &English{})
+		container.Register("foo", &Foo{})
+
+		Convey("When resolving the interface by type", func() {
+			_, err := container.GetByType(greeterType)
+
+			Convey("Then it should be ambiguous", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Ambiguous services of type container.Greeter: "english", "formal"`)
+			})
+		})
+
+		Convey("When the interface is bound to one of them", func() {
+			err := container.Bind(greeterType, "formal")
+
+			Convey("Then resolving the interface should return that service", func() {
+				So(err, ShouldBeNil)
+
+				greeter, err := container.GetByType(greeterType)
+
+				So(err, ShouldBeNil)
+				So(greeter, ShouldEqual, container.MustGet("formal"))
+			})
+
+			Convey("And autowired arguments should receive it", func() {
+				def, _ := container.Register("welcome", NewWelcome)
+				def.AddArguments(nil, nil, argument.New(1))
+
+				welcome, err := container.Get("welcome")
+
+				So(err, ShouldBeNil)
+				So(welcome.(*Welcome).Greeter, ShouldEqual, container.MustGet("formal"))
+				So(container.Validate(), ShouldBeNil)
+			})
+
+			Convey("And binding it again should return an error", func() {
+				err := container.Bind(greeterType, "english")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `container.Greeter is already bound to "formal"`)
+			})
+		})
+
+		Convey("When binding a type that is not an interface", func() {
+			err := container.Bind(reflect.TypeOf(&English{}), "english")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Cannot bind *container.English, which is not an interface")
+			})
+		})
+
+		Convey("When the interface is bound to a missing or unrelated service", func() {
+			container.Bind(greeterType, "foo")
+			container.Bind(reflect.TypeOf((*error)(nil)).Elem(), "missing")
+
+			Convey("Then validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Service "foo" bound to container.Greeter does not implement it
+Binding of error refers to missing service "missing"`)
+			})
+		})
+	})
+}
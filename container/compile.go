@@ -0,0 +1,36 @@
+package container
+
+import "fmt"
+
+// Compile validates the container and then freezes it, so that definitions,
+// aliases, bindings, decorators and parameters can no longer be added. A
+// container failing validation is left unfrozen.
+func (c *Container) Compile() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	c.frozen = true
+	return nil
+}
+
+// Compiled reports whether the container has been compiled
+func (c *Container) Compiled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.frozen
+}
+
+// mutable returns an error when the container is frozen. The caller must
+// hold the container lock.
+func (c *Container) mutable() error {
+	if c.frozen {
+		return fmt.Errorf("Container is compiled and cannot be modified")
+	}
+
+	return nil
+}
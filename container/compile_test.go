@@ -0,0 +1,89 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompile(t *testing.T) {
+	Convey("Given a container with valid definitions", t, func() {
+		container := New()
+		container.Register("greeter", &English{})
+		container.Register("foo", &Foo{})
+		container.SetParameter("times", 3)
+
+		def, _ := container.Register("welcome", NewWelcome)
+		def.AddArguments(reference.New("greeter"), nil, parameter.New("%times%"))
+
+		Convey("When compiling it", func() {
+			err := container.Compile()
+
+			Convey("Then it should be compiled", func() {
+				So(err, ShouldBeNil)
+				So(container.Compiled(), ShouldBeTrue)
+
+				welcome, err := container.Get("welcome")
+
+				So(err, ShouldBeNil)
+				So(welcome.(*Welcome).Times, ShouldEqual, 3)
+			})
+
+			Convey("And it should no longer be modified", func() {
+				_, registerErr := container.Register("bar", &Foo{})
+				setErr := container.Set("bar", &Foo{})
+				aliasErr := container.Alias("hello", "greeter")
+				bindErr := container.Bind(greeterType, "greeter")
+				_, decorateErr := container.Decorate("greeter", func(g *English) *English { return g }, 0)
+				parameterErr := container.SetParameter("times", 4)
+
+				for _, err := range []error{registerErr, setErr, aliasErr, bindErr, decorateErr, parameterErr} {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Container is compiled and cannot be modified")
+				}
+			})
+
+			Convey("And a child container should still accept definitions", func() {
+				child := container.NewChild()
+				_, err := child.Register("bar", &Foo{})
+
+				So(err, ShouldBeNil)
+				So(child.Compiled(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a container with arguments of the wrong type", t, func() {
+		container := New()
+		container.Register("greeter", &English{})
+		container.Register("foo", &Foo{})
+		container.SetParameter("times", "three")
+
+		welcome, _ := container.Register("welcome", NewWelcome)
+		welcome.AddArguments(reference.New("foo"), nil, parameter.New("%times%"))
+
+		other, _ := container.Register("other", NewWelcome)
+		other.AddArguments(nil, nil, argument.New("twice"))
+
+		Convey("When compiling it", func() {
+			err := container.Compile()
+
+			Convey("Then it should report every argument", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 2 of "other": Cannot use string as int
+Argument 0 of "welcome": Cannot use *container.Foo as container.Greeter
+Argument 2 of "welcome": Cannot use string as int`)
+			})
+
+			Convey("And it should not be frozen", func() {
+				So(container.Compiled(), ShouldBeFalse)
+
+				_, err := container.Register("bar", &Foo{})
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,179 @@
+package container
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// These tests are meant to be run with the race detector enabled:
+//
+//	go test -race ./container
+
+func TestConcurrentGetConstructsSharedServiceOnce(t *testing.T) {
+	Convey("Given a service whose constructor takes a while to complete", t, func() {
+		container := New()
+
+		var calls int32
+		container.Register("slow", func() *Foo {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &Foo{}
+		})
+
+		Convey("When many goroutines request it at the same time", func() {
+			const workers = 64
+
+			var wg sync.WaitGroup
+			services := make([]interface{}, workers)
+			errs := make([]error, workers)
+
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+					services[i], errs[i] = container.Get("slow")
+				}(i)
+			}
+
+			wg.Wait()
+
+			Convey("Then the constructor should have been called exactly once", func() {
+				So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+			})
+
+			Convey("And every goroutine should receive the same instance", func() {
+				for i := 0; i < workers; i++ {
+					So(errs[i], ShouldBeNil)
+					So(services[i], ShouldPointTo, services[0])
+				}
+			})
+		})
+	})
+}
+
+func TestConcurrentGetSharesConstructorFailure(t *testing.T) {
+	Convey("Given a service whose constructor fails after a while", t, func() {
+		container := New()
+
+		var calls int32
+		container.Register("failing", func() (*Foo, error) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(10 * time.Millisecond)
+			return nil, fmt.Errorf("unavailable")
+		})
+
+		Convey("When many goroutines request it at the same time", func() {
+			var wg sync.WaitGroup
+			errs := make([]error, 16)
+
+			for i := range errs {
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = container.Get("failing")
+				}(i)
+			}
+
+			wg.Wait()
+
+			Convey("Then every goroutine should receive the error", func() {
+				for _, err := range errs {
+					So(err, ShouldNotBeNil)
+				}
+			})
+
+			Convey("And the failure should not be cached and a later request should retry", func() {
+				before := atomic.LoadInt32(&calls)
+				container.Get("failing")
+				So(atomic.LoadInt32(&calls), ShouldEqual, before+1)
+			})
+		})
+	})
+}
+
+func TestConcurrentRegisterAndGet(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey("When services are registered and requested from many goroutines", func() {
+			var wg sync.WaitGroup
+
+			for i := 0; i < 32; i++ {
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+
+					id := fmt.Sprintf("foo.%d", i)
+					container.Register(id, func() *Foo { return &Foo{Number: i} })
+					container.Get(id)
+					container.Validate()
+				}(i)
+			}
+
+			wg.Wait()
+
+			Convey("Then every service should have been registered and built", func() {
+				for i := 0; i < 32; i++ {
+					foo, err := container.Get(fmt.Sprintf("foo.%d", i))
+
+					So(err, ShouldBeNil)
+					So(foo.(*Foo).Number, ShouldEqual, i)
+				}
+			})
+		})
+	})
+}
+
+func TestConcurrentGetWithCircularDependency(t *testing.T) {
+	Convey(`Given "a" and "b" services depending on each other`, t, func() {
+		container := New()
+
+		a, _ := container.Register("a", func(b *B) *A { return &A{b} })
+		refB := reference.New("b")
+		a.AddArguments(&refB)
+
+		b, _ := container.Register("b", func(a *A) *B { return &B{a} })
+		refA := reference.New("a")
+		b.AddArguments(&refA)
+
+		Convey("When both are requested from different goroutines at the same time", func() {
+			var wg sync.WaitGroup
+			errs := make([]error, 2)
+
+			for i, id := range []string{"a", "b"} {
+				wg.Add(1)
+
+				go func(i int, id string) {
+					defer wg.Done()
+					_, errs[i] = container.Get(id)
+				}(i, id)
+			}
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+
+			Convey("Then both should fail with a circular dependency instead of deadlocking", func() {
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatal("deadlock while resolving a circular dependency concurrently")
+				}
+
+				So(errs[0], ShouldHaveSameTypeAs, &CircularDependencyError{})
+				So(errs[1], ShouldHaveSameTypeAs, &CircularDependencyError{})
+			})
+		})
+	})
+}
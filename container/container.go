@@ -3,81 +3,275 @@ package container
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/drgomesp/cargo/argument"
 	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/method"
+	"github.com/drgomesp/cargo/parameter"
 	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
 )
 
-// Container for dependency injection
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Container for dependency injection. It is safe for concurrent use, and each
+// shared service is constructed exactly once even when many goroutines
+// request it at the same time.
 type Container struct {
+	mu          *sync.RWMutex
+	parent      *Container
 	definitions map[string]definition.Interface
-	services    map[string]interface{}
+	services    *instances
+	parameters  map[string]interface{}
+	aliases     map[string]string
+	bindings    map[reflect.Type]string
+	decorators  map[string][]decorator
+	names       map[string]string
+	idPolicy    IDPolicy
+	frozen      bool
+	external    map[string]bool
+	started     []string
+	hookTimeout time.Duration
+
+	injectUnexported bool
 }
 
+// Option configures a container
+type Option func(c *Container)
+
 // New continer instance
-func New() *Container {
+func New(opts ...Option) *Container {
+	c := &Container{
+		mu:          new(sync.RWMutex),
+		definitions: make(map[string]definition.Interface, 0),
+		services:    newInstances(),
+		parameters:  make(map[string]interface{}, 0),
+		aliases:     make(map[string]string, 0),
+		bindings:    make(map[reflect.Type]string, 0),
+		decorators:  make(map[string][]decorator, 0),
+		names:       make(map[string]string, 0),
+		idPolicy:    CaseInsensitiveIDs,
+		external:    make(map[string]bool, 0),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// NewChild creates a container whose services fall back to the ones of c.
+// Definitions registered on the child shadow the ones of c without changing
+// them, and the services defined by c are still built and shared by c.
+func (c *Container) NewChild() *Container {
 	return &Container{
+		mu:          c.mu,
+		parent:      c,
 		definitions: make(map[string]definition.Interface, 0),
-		services:    make(map[string]interface{}, 0),
+		services:    newInstances(),
+		parameters:  make(map[string]interface{}, 0),
+		aliases:     make(map[string]string, 0),
+		bindings:    make(map[reflect.Type]string, 0),
+		decorators:  make(map[string][]decorator, 0),
+		names:       make(map[string]string, 0),
+		idPolicy:    c.idPolicy,
+		external:    make(map[string]bool, 0),
+		hookTimeout: c.hookTimeout,
+
+		injectUnexported: c.injectUnexported,
 	}
 }
 
 // Register a new service definition
 func (c *Container) Register(id string, arg interface{}) (def definition.Interface, err error) {
-	if _, ok := c.definitions[id]; ok {
-		err = fmt.Errorf(`Definition for "%s" already exists`, id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
 		return
 	}
 
-	def, err = definition.New(arg)
-	c.definitions[id] = def
+	if taken, ok := c.defined(id); ok {
+		err = alreadyExists(id, taken)
+		return
+	}
+
+	if def, err = definition.New(arg); err != nil {
+		return
+	}
 
+	c.definitions[id] = def
+	c.name(id)
 	return
 }
 
 // Set a new service
 func (c *Container) Set(id string, arg interface{}) (err error) {
-	if _, ok := c.definitions[id]; ok {
-		err = fmt.Errorf(`Definition for "%s" already exists`, id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
 		return
 	}
 
-	if c.definitions[id], err = definition.New(arg); err != nil {
+	if taken, ok := c.defined(id); ok {
+		err = alreadyExists(id, taken)
+		return
+	}
+
+	def, err := definition.NewInstance(arg)
+
+	if err != nil {
 		err = fmt.Errorf("Could not create definition")
 		return
 	}
 
-	c.services[id] = arg
+	c.definitions[id] = def
+	c.name(id)
+	c.services.set(id, arg)
+	c.external[id] = true
 	return
 }
 
 // Get a service
 func (c *Container) Get(id string) (service interface{}, err error) {
-	for i := 0; i < 2; i++ {
-		if s, ok := c.services[id]; ok {
-			service = s
-			return
+	return c.get(id, nil, nil)
+}
+
+// GetByType gets the only service whose type is t or, when t is an
+// interface, implements t
+func (c *Container) GetByType(t reflect.Type) (service interface{}, err error) {
+	c.mu.RLock()
+	id, err := c.lookup(t, "")
+	c.mu.RUnlock()
+
+	if err != nil {
+		return
+	}
+
+	return c.Get(id)
+}
+
+// Definition registered under id, in the container or its ancestors
+func (c *Container) Definition(id string) (def definition.Interface, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, def, ok = c.definition(id)
+	return
+}
+
+// get resolves a service, where path holds the chain of services currently
+// being resolved that led to this one and scope, if any, holds the instances
+// of scoped services
+func (c *Container) get(id string, path []string, scope *Scope) (service interface{}, err error) {
+	c.mu.RLock()
+	owner, found, def, ok := c.find(id)
+	c.mu.RUnlock()
+
+	if !ok {
+		err = fmt.Errorf(`No service "%s" was found`, id)
+		return
+	}
+
+	if owner != c {
+		return owner.get(found, path, scope)
+	}
+
+	if indexOf(path, found) >= 0 {
+		err = &CircularDependencyError{Path: append(path[:len(path):len(path)], found)}
+		return
+	}
+
+	chain := append(path[:len(path):len(path)], found)
+
+	// Waiting for a service that is being built by another goroutine would
+	// never end if that service depends on one we are building
+	check := func() error {
+		c.mu.RLock()
+		cycle := c.pathTo(found, path)
+		c.mu.RUnlock()
+
+		if cycle != nil {
+			start := indexOf(path, cycle[len(cycle)-1])
+			return &CircularDependencyError{Path: append(path[start:len(path):len(path)], cycle...)}
 		}
 
-		if def, ok := c.definitions[id]; ok {
-			service, err = c.createService(def)
+		return nil
+	}
 
-			if err != nil {
-				return
-			}
+	switch def.Scope() {
+	case definition.Prototype:
+		return c.createService(found, def, chain, scope)
+	case definition.Scoped:
+		if owner := c.sharedOwner(path); owner != "" {
+			err = fmt.Errorf(`Shared service "%s" cannot depend on scoped service "%s"`, owner, found)
+			return
+		}
 
-			c.services[id] = service
+		if scope == nil {
+			err = fmt.Errorf(`Scoped service "%s" must be resolved within a scope`, found)
 			return
 		}
 
-		id = strings.ToLower(id)
+		return scope.services.get(found, check, func() (interface{}, error) {
+			return c.createService(found, def, chain, scope)
+		})
+	default:
+		return c.services.get(found, check, func() (interface{}, error) {
+			return c.createService(found, def, chain, nil)
+		})
 	}
+}
 
-	err = fmt.Errorf(`No service "%s" was found`, id)
+// definition finds the definition registered under id, in the container or
+// its ancestors, and returns the identifier it was registered with. The
+// caller must hold the container lock.
+func (c *Container) definition(id string) (found string, def definition.Interface, ok bool) {
+	_, found, def, ok = c.find(id)
 	return
 }
 
+// find the container holding the definition registered under id, or under
+// an identifier with the same normal form, looking into the parent container
+// when there is none and following aliases. The caller must hold the
+// container lock.
+func (c *Container) find(id string) (owner *Container, found string, def definition.Interface, ok bool) {
+	return c.resolve(id, nil)
+}
+
+// resolve is find, where seen holds the aliases already followed so that
+// circular aliases are never followed twice
+func (c *Container) resolve(id string, seen map[string]bool) (owner *Container, found string, def definition.Interface, ok bool) {
+	for owner = c; owner != nil; owner = owner.parent {
+		if found, ok = owner.defined(id); !ok {
+			continue
+		}
+
+		if def, ok = owner.definitions[found]; ok {
+			return
+		}
+
+		if target := owner.aliases[found]; !seen[found] {
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+
+			seen[found] = true
+			return owner.resolve(target, seen)
+		}
+	}
+
+	return nil, id, nil, false
+}
+
 // MustGet is a wrapper for Get that panics if service was not found
 func (c *Container) MustGet(id string) interface{} {
 	if service, err := c.Get(id); err != nil {
@@ -87,77 +281,272 @@ func (c *Container) MustGet(id string) interface{} {
 	}
 }
 
-func (c *Container) createService(def definition.Interface) (service interface{}, err error) {
-	obj, _ := c.callConstructor(def)
+func (c *Container) createService(id string, def definition.Interface, path []string, scope *Scope) (service interface{}, err error) {
+	obj, err := c.callConstructor(id, def, path, scope)
+
+	if err != nil {
+		return
+	}
+
+	if def.Template().IsValid() {
+		if err = c.injectFields(id, def, obj, path, scope); err != nil {
+			return
+		}
+	}
 
 	if len(def.MethodCalls()) > 0 {
-		if err = callMethods(def, &obj); err != nil {
+		if err = c.callMethods(id, def, obj, path, scope); err != nil {
 			return
 		}
 	}
 
-	return obj.Interface(), nil
+	return c.decorate(id, obj.Interface(), path, scope)
 }
 
-func (c *Container) callConstructor(def definition.Interface) (obj reflect.Value, err error) {
-	if len(def.Arguments()) > 0 {
-		args := make([]reflect.Value, len(def.Arguments()))
+func (c *Container) callConstructor(id string, def definition.Interface, path []string, scope *Scope) (obj reflect.Value, err error) {
+	constructor := def.Constructor()
 
-		for i, arg := range def.Arguments() {
-			if reference, ok := arg.(reference.Interface); ok {
-				var found interface{}
-				found, err = c.Get(reference.Identifier())
+	if !constructor.IsValid() {
+		template := def.Template()
 
-				if err != nil {
-					return
-				}
+		if !template.IsValid() {
+			err = fmt.Errorf(`Service "%s" has no constructor`, id)
+			return
+		}
+
+		obj = reflect.New(template.Type().Elem())
+
+		if !template.IsNil() {
+			obj.Elem().Set(template.Elem())
+		}
+
+		return
+	}
+
+	fn := constructor.Type()
+	numIn := fn.NumIn()
+
+	if fn.IsVariadic() {
+		numIn--
+	}
+
+	if !fn.IsVariadic() && len(def.Arguments()) > numIn {
+		err = fmt.Errorf(`Constructor of "%s" expects %d arguments`, id, numIn)
+		return
+	}
 
-				args[i] = reflect.ValueOf(found)
-			} else {
-				args[i] = reflect.ValueOf(arg.Value())
+	args := make([]reflect.Value, numIn)
+
+	if len(def.Arguments()) > numIn {
+		args = make([]reflect.Value, len(def.Arguments()))
+	}
+
+	for i := range args {
+		var arg argument.Interface
+
+		if i < len(def.Arguments()) {
+			arg = def.Arguments()[i]
+		}
+
+		if args[i], err = c.resolveArgument(id, arg, parameterType(fn, i), path, scope); err != nil {
+			if !passthrough(err) {
+				err = fmt.Errorf(`Argument %d of "%s": %s`, i, id, err)
 			}
+
+			return
+		}
+	}
+
+	results := constructor.Call(args)
+
+	if len(results) > 1 && !results[1].IsNil() {
+		err = &ResolutionError{ID: id, Path: path, Err: results[1].Interface().(error)}
+		return
+	}
+
+	obj = results[0]
+	return
+}
+
+// resolveArgument resolves an argument of the service id into a value of
+// type t. A nil argument is autowired by looking up a service of type t, and
+// a tagged argument collects every service with its tag.
+func (c *Container) resolveArgument(id string, arg argument.Interface, t reflect.Type, path []string, scope *Scope) (v reflect.Value, err error) {
+	var value interface{}
+
+	switch arg := arg.(type) {
+	case nil:
+		var found string
+
+		c.mu.RLock()
+		found, err = c.lookup(t, id)
+		c.mu.RUnlock()
+
+		if err != nil {
+			return
 		}
 
-		obj = def.Constructor().Call(args)[0]
+		if value, err = c.get(found, path, scope); err != nil {
+			return
+		}
+	case reference.Interface:
+		if value, err = c.get(arg.Identifier(), path, scope); err != nil {
+			return
+		}
+	case parameter.Interface:
+		if value, err = arg.Resolve(c.GetParameter); err != nil {
+			return
+		}
+
+		value = convert(value, t)
+	case tagged.Interface:
+		if value, err = c.collect(arg.Tag(), t, path, scope); err != nil {
+			return
+		}
+	default:
+		value = convert(arg.Value(), t)
+	}
+
+	return argumentValue(value, t)
+}
+
+// lookup finds the identifier of the only service, other than exclude, whose
+// type is t or, when t is an interface, implements t. Services of exactly
+// type t take precedence over the ones implementing it, services bound to t
+// take precedence over both, and services of a container take precedence
+// over the ones of its parent. The caller must hold the container lock.
+func (c *Container) lookup(t reflect.Type, exclude string) (id string, err error) {
+	shadowed := map[string]bool{exclude: true}
+
+	for current := c; current != nil; current = current.parent {
+		if bound, ok := current.bindings[t]; ok && bound != exclude {
+			id = bound
+			return
+		}
+
+		var exact, implementing []string
 
-		for i, arg := range args {
-			field := obj.Elem().Field(i)
+		for candidate, def := range current.definitions {
+			if shadowed[candidate] || def.Type() == nil {
+				continue
+			}
 
-			if field.IsValid() && field.CanSet() {
-				field.Set(arg)
+			if def.Type() == t {
+				exact = append(exact, candidate)
+			} else if t.Kind() == reflect.Interface && def.Type().Implements(t) {
+				implementing = append(implementing, candidate)
 			}
 		}
-	} else {
-		obj = def.Constructor().Call(make([]reflect.Value, 0))[0]
+
+		candidates := exact
+
+		if len(candidates) == 0 {
+			candidates = implementing
+		}
+
+		sort.Strings(candidates)
+
+		switch len(candidates) {
+		case 0:
+		case 1:
+			id = candidates[0]
+			return
+		default:
+			err = fmt.Errorf(`Ambiguous services of type %s: "%s"`, t, strings.Join(candidates, `", "`))
+			return
+		}
+
+		for candidate := range current.definitions {
+			shadowed[candidate] = true
+		}
+	}
+
+	err = notFoundError(fmt.Sprintf("No service of type %s was found", t))
+	return
+}
+
+// parameterType returns the type of the i-th parameter of fn, unwrapping the
+// element type of a trailing variadic parameter
+func parameterType(fn reflect.Type, i int) reflect.Type {
+	if fn.IsVariadic() && i >= fn.NumIn()-1 {
+		return fn.In(fn.NumIn() - 1).Elem()
 	}
 
-	return obj, nil
+	return fn.In(i)
+}
+
+// argumentValue converts a resolved argument into a value assignable to t
+func argumentValue(value interface{}, t reflect.Type) (v reflect.Value, err error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			v = reflect.Zero(t)
+		default:
+			err = fmt.Errorf("Cannot use nil as %s", t)
+		}
+
+		return
+	}
+
+	v = reflect.ValueOf(value)
+
+	if !v.Type().AssignableTo(t) {
+		err = fmt.Errorf("Cannot use %s as %s", v.Type(), t)
+	}
+
+	return
 }
 
-func callMethods(def definition.Interface, obj *reflect.Value) (err error) {
+func (c *Container) callMethods(id string, def definition.Interface, obj reflect.Value, path []string, scope *Scope) (err error) {
 	for _, method := range def.MethodCalls() {
-		if m, ok := obj.Type().MethodByName(method.Name); ok {
-			if m.Func.Type().NumIn() > 0 {
-				numArgs := m.Func.Type().NumIn() - 1
+		var fn reflect.Type
 
-				if len(method.Args) != numArgs {
-					err = fmt.Errorf(`Method "%s" expects %d arguments`, method.Name, numArgs)
-					return
-				}
+		if fn, err = methodSignature(obj.Type(), method); err != nil {
+			return
+		}
 
-				args := make([]reflect.Value, numArgs)
+		args := make([]reflect.Value, len(method.Args))
 
-				for i, arg := range method.Args {
-					args[i] = reflect.ValueOf(arg.Value())
+		for i, arg := range method.Args {
+			if args[i], err = c.resolveArgument(id, arg, parameterType(fn, i), path, scope); err != nil {
+				if !passthrough(err) {
+					err = fmt.Errorf(`Argument %d of method "%s" of "%s": %s`, i, method.Name, id, err)
 				}
 
-				args = append([]reflect.Value{*obj}, args...)
-
-				m.Func.Call(args)
-			} else {
-				m.Func.Call(nil)
+				return
 			}
 		}
+
+		results := obj.MethodByName(method.Name).Call(args)
+
+		if n := len(results); n > 0 && fn.Out(n-1) == errorType && !results[n-1].IsNil() {
+			err = &ResolutionError{ID: id, Path: path, Err: fmt.Errorf(`Method "%s": %w`, method.Name, results[n-1].Interface().(error))}
+			return
+		}
+	}
+
+	return
+}
+
+// methodSignature returns the signature of the method called on services of
+// type t, checking that it exists, is exported and accepts its arguments
+func methodSignature(t reflect.Type, method *method.Method) (fn reflect.Type, err error) {
+	if r, _ := utf8.DecodeRuneInString(method.Name); !unicode.IsUpper(r) {
+		err = fmt.Errorf(`Method "%s" of %s is not exported`, method.Name, t)
+		return
+	}
+
+	fn, ok := methodType(t, method.Name)
+
+	if !ok {
+		err = fmt.Errorf(`Method "%s" does not exist on %s`, method.Name, t)
+		return
+	}
+
+	numArgs := fn.NumIn()
+
+	if len(method.Args) != numArgs && !(fn.IsVariadic() && len(method.Args) >= numArgs-1) {
+		err = fmt.Errorf(`Method "%s" expects %d arguments`, method.Name, numArgs)
 	}
 
 	return
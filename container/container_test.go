@@ -1,6 +1,8 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -293,6 +295,159 @@ func TestGetServiceRegisteredWithConstructorFunctionAndArguments(t *testing.T) {
 	})
 }
 
+func TestGetServiceExecutesConstructorFunction(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey("And a constructor function that computes derived state", func() {
+			type Foo struct {
+				Number int
+				Double int
+			}
+
+			calls := 0
+			NewFoo := func(number int) *Foo {
+				calls++
+				return &Foo{Number: number, Double: number * 2}
+			}
+
+			def, _ := container.Register("foo", NewFoo)
+			def.AddArguments(argument.New(21))
+
+			Convey(`When requesting for that service named "foo" from the container`, func() {
+				foo, err := container.Get("foo")
+
+				Convey("Then it should return an empty error", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("And the constructor function should have been called exactly once", func() {
+					So(calls, ShouldEqual, 1)
+				})
+
+				Convey("And the returned service should be the one built by the constructor", func() {
+					So(foo.(*Foo).Number, ShouldEqual, 21)
+					So(foo.(*Foo).Double, ShouldEqual, 42)
+				})
+			})
+		})
+	})
+}
+
+func TestGetServiceWithArgumentOfWrongType(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey("And a constructor function registered with an argument of the wrong type", func() {
+			type Foo struct{}
+			NewFoo := func(number int) *Foo {
+				return &Foo{}
+			}
+
+			def, _ := container.Register("foo", NewFoo)
+			def.AddArguments(argument.New("not_a_number"))
+
+			Convey(`When requesting for that service named "foo" from the container`, func() {
+				_, err := container.Get("foo")
+
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, `Argument 0 of "foo": Cannot use string as int`)
+				})
+			})
+		})
+	})
+}
+
+func TestGetServiceWithMissingConstructorArguments(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey("And a constructor function registered without its arguments and no service to autowire", func() {
+			type Foo struct{}
+			NewFoo := func(number int, text string) *Foo {
+				return &Foo{}
+			}
+
+			container.Register("foo", NewFoo)
+
+			Convey(`When requesting for that service named "foo" from the container`, func() {
+				_, err := container.Get("foo")
+
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, `Argument 0 of "foo": No service of type int was found`)
+				})
+			})
+		})
+	})
+}
+
+func TestGetServiceWithConstructorFunctionReturningAnError(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey(`And a "db" service whose constructor fails`, func() {
+			type DB struct{}
+			type Repository struct{ DB *DB }
+
+			calls := 0
+			failure := errors.New("connection refused")
+
+			container.Register("db", func() (*DB, error) {
+				calls++
+				return nil, failure
+			})
+
+			def, _ := container.Register("repository", func(db *DB) (*Repository, error) {
+				return &Repository{db}, nil
+			})
+			ref := reference.New("db")
+			def.AddArguments(&ref)
+
+			Convey(`When requesting for the "repository" service that depends on it`, func() {
+				_, err := container.Get("repository")
+
+				Convey("Then it should return the constructor error wrapped with the resolution path", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, `Could not create service "db" (repository -> db): connection refused`)
+					So(errors.Is(err, failure), ShouldBeTrue)
+
+					var resolution *ResolutionError
+					So(errors.As(err, &resolution), ShouldBeTrue)
+					So(resolution.ID, ShouldEqual, "db")
+					So(resolution.Path, ShouldResemble, []string{"repository", "db"})
+				})
+
+				Convey("And the failed service should not be cached", func() {
+					container.Get("db")
+					So(calls, ShouldEqual, 2)
+				})
+
+				Convey("And MustGet should panic", func() {
+					So(func() { container.MustGet("repository") }, ShouldPanic)
+				})
+			})
+		})
+
+		Convey(`And a "foo" service whose constructor succeeds with a nil error`, func() {
+			type Foo struct{}
+			container.Register("foo", func() (*Foo, error) {
+				return &Foo{}, nil
+			})
+
+			Convey("When requesting for that service", func() {
+				foo, err := container.Get("foo")
+
+				Convey("Then it should return the service and an empty error", func() {
+					So(err, ShouldBeNil)
+					So(foo, ShouldHaveSameTypeAs, &Foo{})
+				})
+			})
+		})
+	})
+}
+
 type Foo struct {
 	Number int
 	Text   string
@@ -487,3 +642,214 @@ func TestMustGetNonExistingService(t *testing.T) {
 		})
 	})
 }
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (e *English) Greet() string {
+	return "hello"
+}
+
+type Welcome struct {
+	Greeter Greeter
+	Foo     *Foo
+	Times   int
+}
+
+func NewWelcome(greeter Greeter, foo *Foo, times int) *Welcome {
+	return &Welcome{greeter, foo, times}
+}
+
+func TestGetServiceWithAutowiredArguments(t *testing.T) {
+	Convey("Given a service container instance with a greeter and a foo service", t, func() {
+		container := New()
+		english := &English{}
+		foo := &Foo{1, "foo"}
+		container.Set("greeter", english)
+		container.Set("foo", foo)
+
+		Convey("When registering a service whose constructor arguments are only partially given", func() {
+			def, _ := container.Register("welcome", NewWelcome)
+			def.AddArguments(nil, nil, argument.New(3))
+
+			Convey("And requesting for that service", func() {
+				welcome, err := container.Get("welcome")
+
+				Convey("Then there should be no error", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("And the missing arguments should be autowired by type", func() {
+					So(welcome.(*Welcome).Greeter, ShouldEqual, english)
+					So(welcome.(*Welcome).Foo, ShouldEqual, foo)
+					So(welcome.(*Welcome).Times, ShouldEqual, 3)
+				})
+			})
+		})
+
+		Convey("When two services match the type of a constructor argument", func() {
+			container.Set("other.foo", &Foo{2, "other"})
+			def, _ := container.Register("welcome", NewWelcome)
+			def.AddArguments(nil, nil, argument.New(3))
+
+			Convey("Then requesting for that service should report the ambiguity", func() {
+				_, err := container.Get("welcome")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 1 of "welcome": Ambiguous services of type *container.Foo: "foo", "other.foo"`)
+			})
+
+			Convey("And an explicit argument should override autowiring at that position", func() {
+				ref := reference.New("other.foo")
+				def.(*definition.Definition).Arguments()[1] = &ref
+
+				welcome, err := container.Get("welcome")
+
+				So(err, ShouldBeNil)
+				So(welcome.(*Welcome).Foo.Text, ShouldEqual, "other")
+			})
+		})
+
+		Convey("When no service matches the type of a constructor argument", func() {
+			container.Register("welcome", NewWelcome)
+
+			Convey("Then requesting for that service should report the missing provider", func() {
+				_, err := container.Get("welcome")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 2 of "welcome": No service of type int was found`)
+			})
+		})
+	})
+}
+
+func TestNewChild(t *testing.T) {
+	Convey("Given a parent container with a shared service and a greeter", t, func() {
+		parent := New()
+		parent.Register("foo", func() *Foo { return &Foo{1, "parent"} })
+		parent.Set("greeter", &English{})
+
+		Convey("When a child container is created from it", func() {
+			child := parent.NewChild()
+
+			Convey("Then the child should fall back to the services of the parent", func() {
+				foo, err := child.Get("foo")
+
+				So(err, ShouldBeNil)
+				So(foo, ShouldPointTo, parent.MustGet("foo"))
+			})
+
+			Convey("And a definition set in the child should shadow the parent without changing it", func() {
+				_, err := child.Register("foo", func() *Foo { return &Foo{2, "child"} })
+
+				So(err, ShouldBeNil)
+				So(child.MustGet("foo").(*Foo).Text, ShouldEqual, "child")
+				So(parent.MustGet("foo").(*Foo).Text, ShouldEqual, "parent")
+			})
+
+			Convey("And services of the child should be autowired with the ones of the parent", func() {
+				def, _ := child.Register("welcome", NewWelcome)
+				def.AddArguments(nil, nil, argument.New(1))
+
+				welcome, err := child.Get("welcome")
+
+				So(err, ShouldBeNil)
+				So(welcome.(*Welcome).Foo, ShouldPointTo, parent.MustGet("foo"))
+				So(welcome.(*Welcome).Greeter, ShouldPointTo, parent.MustGet("greeter"))
+			})
+
+			Convey("And services of the child should not be visible from the parent", func() {
+				child.Set("bar", &Foo{})
+
+				_, err := parent.Get("bar")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestShutdownChild(t *testing.T) {
+	Convey("Given closable services built by a parent and by its child", t, func() {
+		closed := make([]string, 0)
+		parent := New()
+		parent.Register("parent.closable", func() *Closable { return &Closable{&closed} })
+
+		child := parent.NewChild()
+		child.Register("child.closable", func() *Closable { return &Closable{&closed} })
+
+		child.MustGet("parent.closable")
+		child.MustGet("child.closable")
+
+		Convey("When the child is shut down", func() {
+			err := child.Shutdown(context.Background())
+
+			Convey("Then only the instances of the child should be closed", func() {
+				So(err, ShouldBeNil)
+				So(closed, ShouldHaveLength, 1)
+			})
+
+			Convey("And the instances of the parent should be kept", func() {
+				So(parent.services.len(), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+type Logger struct{ Name string }
+
+type Repository struct{ Logger *Logger }
+
+func (r *Repository) SetLogger(logger *Logger) {
+	r.Logger = logger
+}
+
+func TestGetServiceWithReferenceInMethodCall(t *testing.T) {
+	Convey(`Given a service container instance with a "logger" service`, t, func() {
+		container := New()
+		logger := &Logger{"app"}
+		container.Set("logger", logger)
+
+		Convey("When registering a service with a method call referencing it", func() {
+			def, _ := container.Register("repository", func() *Repository { return &Repository{} })
+			def.AddMethodCall(method.New("SetLogger", reference.New("logger")))
+
+			Convey("Then the referenced service should be injected through the method", func() {
+				repository, err := container.Get("repository")
+
+				So(err, ShouldBeNil)
+				So(repository.(*Repository).Logger, ShouldPointTo, logger)
+			})
+		})
+
+		Convey("When the method call references a service of the wrong type", func() {
+			container.Set("foo", &Foo{})
+			def, _ := container.Register("repository", func() *Repository { return &Repository{} })
+			def.AddMethodCall(method.New("SetLogger", reference.New("foo")))
+
+			Convey("Then requesting for the service should return an error", func() {
+				_, err := container.Get("repository")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of method "SetLogger" of "repository": Cannot use *container.Foo as *container.Logger`)
+			})
+		})
+
+		Convey("When the method call references a service depending on the service itself", func() {
+			def, _ := container.Register("repository", func() *Repository { return &Repository{} })
+			def.AddMethodCall(method.New("SetLogger", reference.New("scoped.logger")))
+
+			logger, _ := container.Register("scoped.logger", func(r *Repository) *Logger { return &Logger{} })
+			logger.AddArguments(reference.New("repository"))
+
+			Convey("Then validating the container should report the cycle", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: repository -> scoped.logger -> repository")
+			})
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/definition"
+)
+
+type decorator struct {
+	def      definition.Interface
+	priority int
+}
+
+// Decorate wraps the service id, once built and before it is shared, with a
+// decorator function receiving the service as its first parameter. The
+// returned definition holds the arguments of the remaining parameters, which
+// are autowired by default. Decorators are applied by increasing priority,
+// so that the one with the highest priority wraps all the others.
+func (c *Container) Decorate(id string, fn interface{}, priority int) (def definition.Interface, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
+		return
+	}
+
+	owner, found, service, ok := c.find(id)
+
+	if !ok || owner != c {
+		err = fmt.Errorf(`No service "%s" was found`, id)
+		return
+	}
+
+	if c.external[found] {
+		err = fmt.Errorf(`Service "%s" was set as an instance and cannot be decorated`, found)
+		return
+	}
+
+	if def, err = definition.New(fn); err != nil {
+		return
+	}
+
+	if !def.Constructor().IsValid() {
+		def, err = nil, fmt.Errorf("Decorator must be a function")
+		return
+	}
+
+	t := def.Constructor().Type()
+
+	switch {
+	case t.NumIn() == 0 || t.IsVariadic():
+		err = fmt.Errorf("Decorator function must receive the decorated service first")
+	case !service.Type().AssignableTo(t.In(0)) && service.Type().Kind() != reflect.Interface:
+		err = fmt.Errorf(`Decorator of "%s" expects %s, not %s`, found, t.In(0), service.Type())
+	case !def.Type().AssignableTo(service.Type()):
+		err = fmt.Errorf(`Decorator of "%s" returns %s, not %s`, found, def.Type(), service.Type())
+	}
+
+	if err != nil {
+		def = nil
+		return
+	}
+
+	decorators := append(c.decorators[found], decorator{def, priority})
+
+	sort.SliceStable(decorators, func(i, j int) bool {
+		return decorators[i].priority < decorators[j].priority
+	})
+
+	c.decorators[found] = decorators
+	return
+}
+
+// decorate wraps the service id with its decorators
+func (c *Container) decorate(id string, service interface{}, path []string, scope *Scope) (interface{}, error) {
+	c.mu.RLock()
+	decorators := c.decorators[id]
+	c.mu.RUnlock()
+
+	for i, d := range decorators {
+		fn := d.def.Constructor()
+		args := make([]reflect.Value, fn.Type().NumIn())
+
+		var err error
+
+		if args[0], err = argumentValue(service, fn.Type().In(0)); err != nil {
+			return nil, fmt.Errorf(`Decorator %d of "%s": %s`, i, id, err)
+		}
+
+		for j := 1; j < len(args); j++ {
+			var arg argument.Interface
+
+			if j <= len(d.def.Arguments()) {
+				arg = d.def.Arguments()[j-1]
+			}
+
+			if args[j], err = c.resolveArgument(id, arg, fn.Type().In(j), path, scope); err != nil {
+				if !passthrough(err) {
+					err = fmt.Errorf(`Argument %d of decorator %d of "%s": %s`, j, i, id, err)
+				}
+
+				return nil, err
+			}
+		}
+
+		results := fn.Call(args)
+
+		if len(results) > 1 && !results[1].IsNil() {
+			return nil, &ResolutionError{ID: id, Path: path, Err: results[1].Interface().(error)}
+		}
+
+		service = results[0].Interface()
+	}
+
+	return service, nil
+}
+
+// decoratorDependencies returns the identifiers of the services the
+// decorators of the service id depend on, along with the errors found while
+// resolving them statically. The caller must hold the container lock.
+func (c *Container) decoratorDependencies(id string) (deps []string, errs []error) {
+	owner, found, _, ok := c.find(id)
+
+	if !ok {
+		return
+	}
+
+	for i, d := range owner.decorators[found] {
+		fn := d.def.Constructor().Type()
+
+		if len(d.def.Arguments()) > fn.NumIn()-1 {
+			errs = append(errs, fmt.Errorf(`Decorator %d of "%s" expects %d arguments`, i, found, fn.NumIn()-1))
+			continue
+		}
+
+		for j := 1; j < fn.NumIn(); j++ {
+			var arg argument.Interface
+
+			if j <= len(d.def.Arguments()) {
+				arg = d.def.Arguments()[j-1]
+			}
+
+			dep, err := c.dependency(found, arg, fn.In(j))
+
+			if err == nil {
+				err = c.checkArgument(arg, fn.In(j))
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf(`Argument %d of decorator %d of "%s": %s`, j, i, found, err))
+			} else {
+				deps = append(deps, dep...)
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,134 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Loud struct {
+	Inner Greeter
+	Times int
+}
+
+func (l *Loud) Greet() string {
+	return strings.Repeat(strings.ToUpper(l.Inner.Greet()), l.Times)
+}
+
+type Logged struct {
+	Inner  Greeter
+	Logger *Logger
+}
+
+func (l *Logged) Greet() string {
+	return l.Logger.Name + ": " + l.Inner.Greet()
+}
+
+func TestDecorate(t *testing.T) {
+	Convey("Given a container with a greeter service", t, func() {
+		container := New()
+		container.Set("logger", &Logger{"app"})
+		container.Register("greeter", func() Greeter { return &English{} })
+
+		Convey("When decorators are added with different priorities", func() {
+			loud, err := container.Decorate("greeter", func(inner Greeter, times int) Greeter {
+				return &Loud{inner, times}
+			}, 20)
+			So(err, ShouldBeNil)
+			loud.AddArguments(argument.New(2))
+
+			_, err = container.Decorate("greeter", func(inner Greeter, logger *Logger) Greeter {
+				return &Logged{inner, logger}
+			}, 10)
+			So(err, ShouldBeNil)
+
+			Convey("Then the service should be wrapped by increasing priority", func() {
+				greeter, err := container.Get("greeter")
+
+				So(err, ShouldBeNil)
+				So(greeter.(Greeter).Greet(), ShouldEqual, "APP: HELLOAPP: HELLO")
+				So(greeter.(*Loud).Inner.(*Logged).Inner, ShouldHaveSameTypeAs, &English{})
+			})
+
+			Convey("And the decorated instance should be shared", func() {
+				So(container.MustGet("greeter"), ShouldPointTo, container.MustGet("greeter"))
+				So(container.Validate(), ShouldBeNil)
+			})
+		})
+
+		Convey("When a decorator depends on the decorated service", func() {
+			def, _ := container.Decorate("greeter", func(inner Greeter, other Greeter) Greeter {
+				return inner
+			}, 0)
+			def.AddArguments(reference.New("greeter"))
+
+			Convey("Then requesting for the service should report the cycle", func() {
+				_, err := container.Get("greeter")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: greeter -> greeter")
+			})
+
+			Convey("And validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: greeter -> greeter")
+			})
+		})
+
+		Convey("When a decorator refers to a missing service", func() {
+			def, _ := container.Decorate("greeter", func(inner Greeter, foo *Foo) Greeter {
+				return inner
+			}, 0)
+			def.AddArguments(reference.New("foo"))
+
+			Convey("Then validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 1 of decorator 0 of "greeter": No service "foo" was found`)
+			})
+		})
+
+		Convey("When decorating a missing service", func() {
+			_, err := container.Decorate("spanish", func(inner Greeter) Greeter { return inner }, 0)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `No service "spanish" was found`)
+			})
+		})
+
+		Convey("When decorating a service set as an instance", func() {
+			_, err := container.Decorate("logger", func(inner *Logger) *Logger { return inner }, 0)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Service "logger" was set as an instance and cannot be decorated`)
+			})
+		})
+
+		Convey("When a decorator returns another type", func() {
+			_, err := container.Decorate("greeter", func(inner Greeter) *Logger { return &Logger{} }, 0)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Decorator of "greeter" returns *container.Logger, not container.Greeter`)
+			})
+		})
+
+		Convey("When a decorator does not receive the service", func() {
+			container.Register("foo", &Foo{})
+			_, err := container.Decorate("foo", func(logger *Logger) *Foo { return &Foo{} }, 0)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Decorator of "foo" expects *container.Logger, not *container.Foo`)
+			})
+		})
+	})
+}
@@ -0,0 +1,64 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolutionError is returned when the constructor of a service fails. It
+// carries the identifier of the failing service and the chain of services
+// that was being resolved when the failure happened.
+type ResolutionError struct {
+	ID   string
+	Path []string
+	Err  error
+}
+
+// Error message including the resolution path
+func (e *ResolutionError) Error() string {
+	return fmt.Sprintf(`Could not create service "%s" (%s): %s`, e.ID, strings.Join(e.Path, " -> "), e.Err)
+}
+
+// Unwrap returns the error returned by the constructor
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// CircularDependencyError is returned when a service depends on itself,
+// either directly or through other services
+type CircularDependencyError struct {
+	Path []string
+}
+
+// Error message including the cycle
+func (e *CircularDependencyError) Error() string {
+	return fmt.Sprintf("Circular dependency: %s", strings.Join(e.Path, " -> "))
+}
+
+// passthrough reports whether err already describes where the resolution
+// failed, so it must be returned unchanged by the services depending on it
+func passthrough(err error) bool {
+	switch err.(type) {
+	case *ResolutionError, *CircularDependencyError:
+		return true
+	}
+
+	return false
+}
+
+// notFoundError is returned when no service matches a type
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e)
+}
+
+// alreadyExists is the error of registering id when taken already holds its
+// normal form
+func alreadyExists(id, taken string) error {
+	if id == taken {
+		return fmt.Errorf(`Definition for "%s" already exists`, id)
+	}
+
+	return fmt.Errorf(`Definition for "%s" conflicts with "%s"`, id, taken)
+}
@@ -0,0 +1,40 @@
+package container
+
+import "strings"
+
+// IDPolicy normalises service identifiers. Identifiers with the same normal
+// form refer to the same service and cannot be registered twice.
+type IDPolicy func(id string) string
+
+// ExactIDs is the policy under which identifiers only match themselves
+func ExactIDs(id string) string {
+	return id
+}
+
+// CaseInsensitiveIDs is the default policy, under which identifiers match
+// regardless of their case
+func CaseInsensitiveIDs(id string) string {
+	return strings.ToLower(id)
+}
+
+// WithIDPolicy sets the policy normalising the identifiers of services and
+// aliases. Child containers inherit the policy of their parent.
+func WithIDPolicy(policy IDPolicy) Option {
+	return func(c *Container) {
+		c.idPolicy = policy
+	}
+}
+
+// defined reports whether id, once normalised, is taken by a definition or
+// an alias of the container, returning the identifier taking it. The caller
+// must hold the container lock.
+func (c *Container) defined(id string) (taken string, ok bool) {
+	taken, ok = c.names[c.idPolicy(id)]
+	return
+}
+
+// name records id as taking its normal form. The caller must hold the
+// container lock.
+func (c *Container) name(id string) {
+	c.names[c.idPolicy(id)] = id
+}
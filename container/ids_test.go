@@ -0,0 +1,85 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIDPolicy(t *testing.T) {
+	Convey("Given a container with the default identifier policy", t, func() {
+		container := New()
+		container.Register("Foo.Service", &Foo{})
+
+		Convey("Then the service should be found regardless of the case", func() {
+			lower, err := container.Get("foo.service")
+			So(err, ShouldBeNil)
+
+			upper, err := container.NewChild().Get("FOO.SERVICE")
+			So(err, ShouldBeNil)
+
+			So(lower, ShouldPointTo, upper)
+		})
+
+		Convey("When registering an identifier differing only in case", func() {
+			_, err := container.Register("foo.service", &Foo{})
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Definition for "foo.service" conflicts with "Foo.Service"`)
+			})
+		})
+
+		Convey("When setting or aliasing an identifier differing only in case", func() {
+			setErr := container.Set("FOO.SERVICE", &Foo{})
+			aliasErr := container.Alias("foo.SERVICE", "bar")
+
+			Convey("Then it should return an error", func() {
+				So(setErr, ShouldNotBeNil)
+				So(setErr.Error(), ShouldEqual, `Definition for "FOO.SERVICE" conflicts with "Foo.Service"`)
+				So(aliasErr, ShouldNotBeNil)
+				So(aliasErr.Error(), ShouldEqual, `Definition for "foo.SERVICE" conflicts with "Foo.Service"`)
+			})
+		})
+	})
+
+	Convey("Given a container with exact identifiers", t, func() {
+		container := New(WithIDPolicy(ExactIDs))
+		container.Register("Foo", &Foo{})
+
+		Convey("Then identifiers differing in case should be distinct services", func() {
+			_, err := container.Register("foo", &Foo{})
+			So(err, ShouldBeNil)
+
+			So(container.MustGet("Foo"), ShouldNotPointTo, container.MustGet("foo"))
+		})
+
+		Convey("Then a service should not be found with another case", func() {
+			_, err := container.Get("FOO")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `No service "FOO" was found`)
+		})
+	})
+
+	Convey("Given a container with a custom identifier policy", t, func() {
+		container := New(WithIDPolicy(func(id string) string {
+			return strings.ReplaceAll(strings.ToLower(id), "_", ".")
+		}))
+		container.Register("db_pool", &Foo{})
+
+		Convey("Then the service should be found under any identifier with the same normal form", func() {
+			pool, err := container.Get("DB.pool")
+
+			So(err, ShouldBeNil)
+			So(pool, ShouldPointTo, container.MustGet("db_pool"))
+		})
+
+		Convey("And a child container should inherit the policy", func() {
+			_, err := container.NewChild().Get("db.POOL")
+
+			So(err, ShouldBeNil)
+		})
+	})
+}
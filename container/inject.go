@@ -0,0 +1,120 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/reference"
+)
+
+// injection of a service into a struct field tagged with `inject:"id"`, or
+// `inject:""` to autowire it by type. An optional injection, tagged with
+// `inject:"id,optional"`, leaves the field untouched when no service exists.
+type injection struct {
+	field    reflect.StructField
+	arg      argument.Interface
+	optional bool
+}
+
+// WithUnexportedInjection allows services to be injected into unexported
+// struct fields
+func WithUnexportedInjection() Option {
+	return func(c *Container) {
+		c.injectUnexported = true
+	}
+}
+
+// injections of the fields of the structs pointed to by values of type t
+func injections(t reflect.Type) (found []injection) {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.Elem().NumField(); i++ {
+		field := t.Elem().Field(i)
+		tag, ok := field.Tag.Lookup("inject")
+
+		if !ok {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		inject := injection{field: field}
+
+		if options[0] != "" {
+			inject.arg = reference.New(options[0])
+		}
+
+		for _, option := range options[1:] {
+			inject.optional = inject.optional || option == "optional"
+		}
+
+		found = append(found, inject)
+	}
+
+	return
+}
+
+// injectFields of a service built from a pointer definition
+func (c *Container) injectFields(id string, def definition.Interface, obj reflect.Value, path []string, scope *Scope) (err error) {
+	for _, inject := range injections(def.Type()) {
+		if err = c.checkInjection(inject); err != nil {
+			err = fmt.Errorf(`Field "%s" of "%s": %s`, inject.field.Name, id, err)
+			return
+		}
+
+		if inject.optional && !c.provides(id, inject) {
+			continue
+		}
+
+		var value reflect.Value
+
+		if value, err = c.resolveArgument(id, inject.arg, inject.field.Type, path, scope); err != nil {
+			if !passthrough(err) {
+				err = fmt.Errorf(`Field "%s" of "%s": %s`, inject.field.Name, id, err)
+			}
+
+			return
+		}
+
+		field := obj.Elem().FieldByIndex(inject.field.Index)
+
+		if !inject.field.IsExported() {
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+
+		field.Set(value)
+	}
+
+	return
+}
+
+// checkInjection checks that the field of an injection can be set
+func (c *Container) checkInjection(inject injection) error {
+	if !inject.field.IsExported() && !c.injectUnexported {
+		return fmt.Errorf("Field is not exported")
+	}
+
+	return nil
+}
+
+// provides reports whether a service exists for an injection into a field of
+// the service id
+func (c *Container) provides(id string, inject injection) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if ref, ok := inject.arg.(reference.Interface); ok {
+		_, _, found := c.definition(ref.Identifier())
+		return found
+	}
+
+	_, err := c.lookup(inject.field.Type, id)
+	_, missing := err.(notFoundError)
+
+	return !missing
+}
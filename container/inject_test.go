@@ -0,0 +1,113 @@
+package container
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type UsersHandler struct {
+	Logger     *Logger     `inject:"logger"`
+	Greeter    Greeter     `inject:""`
+	Foo        *Foo        `inject:"foo,optional"`
+	Repository *Repository `inject:",optional"`
+	Prefix     string
+}
+
+type PrivateHandler struct {
+	logger *Logger `inject:"logger"`
+}
+
+func TestGetServiceWithInjectedFields(t *testing.T) {
+	Convey("Given a container with a logger and a greeter", t, func() {
+		container := New()
+		logger := &Logger{"app"}
+		english := &English{}
+		container.Set("logger", logger)
+		container.Set("greeter", english)
+
+		Convey("When registering a struct pointer with tagged fields", func() {
+			container.Register("handler", &UsersHandler{Prefix: "/users"})
+
+			Convey("And requesting for that service", func() {
+				handler, err := container.Get("handler")
+
+				Convey("Then there should be no error", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("And the tagged fields should be injected by identifier or type", func() {
+					So(handler.(*UsersHandler).Logger, ShouldPointTo, logger)
+					So(handler.(*UsersHandler).Greeter, ShouldPointTo, english)
+				})
+
+				Convey("And the optional fields without a service should be left untouched", func() {
+					So(handler.(*UsersHandler).Foo, ShouldBeNil)
+					So(handler.(*UsersHandler).Repository, ShouldBeNil)
+				})
+
+				Convey("And the other fields should be copied from the registered value", func() {
+					So(handler.(*UsersHandler).Prefix, ShouldEqual, "/users")
+				})
+			})
+
+			Convey("And validating the container", func() {
+				Convey("Then it should return an empty error", func() {
+					So(container.Validate(), ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("When registering a struct pointer whose required field cannot be injected", func() {
+			container.Register("broken", &struct {
+				Foo *Foo `inject:"foo"`
+			}{})
+
+			Convey("Then requesting for that service should return an error", func() {
+				_, err := container.Get("broken")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Field "Foo" of "broken": No service "foo" was found`)
+			})
+
+			Convey("And validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Field "Foo" of "broken": No service "foo" was found`)
+			})
+		})
+	})
+}
+
+func TestGetServiceWithInjectedUnexportedFields(t *testing.T) {
+	Convey("Given a logger service", t, func() {
+		logger := &Logger{"app"}
+
+		Convey("When a struct with a tagged unexported field is registered in a default container", func() {
+			container := New()
+			container.Set("logger", logger)
+			container.Register("handler", &PrivateHandler{})
+
+			Convey("Then requesting for it should return an error", func() {
+				_, err := container.Get("handler")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Field "logger" of "handler": Field is not exported`)
+			})
+		})
+
+		Convey("When it is registered in a container allowing unexported injection", func() {
+			container := New(WithUnexportedInjection())
+			container.Set("logger", logger)
+			container.Register("handler", &PrivateHandler{})
+
+			Convey("Then the unexported field should be injected", func() {
+				handler, err := container.Get("handler")
+
+				So(err, ShouldBeNil)
+				So(handler.(*PrivateHandler).logger, ShouldPointTo, logger)
+			})
+		})
+	})
+}
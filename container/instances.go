@@ -0,0 +1,137 @@
+package container
+
+import (
+	"fmt"
+	"sync"
+)
+
+// instances of services living for the same lifetime, along with the
+// constructions in progress for that lifetime
+type instances struct {
+	mu       sync.Mutex
+	services map[string]interface{}
+	calls    map[string]*call
+	order    []string
+}
+
+// call of a service constructor in progress, shared by every goroutine that
+// requests the service while it is being built
+type call struct {
+	done    chan struct{}
+	service interface{}
+	err     error
+}
+
+func newInstances() *instances {
+	return &instances{
+		services: make(map[string]interface{}, 0),
+		calls:    make(map[string]*call, 0),
+	}
+}
+
+// get the instance of id, building it with create unless it already exists.
+// When another goroutine is building it, check is called before waiting for
+// that construction to complete, and an error from check aborts the wait.
+// The instance is cached only if it was successfully built.
+func (i *instances) get(id string, check func() error, create func() (interface{}, error)) (service interface{}, err error) {
+	i.mu.Lock()
+
+	if s, ok := i.services[id]; ok {
+		i.mu.Unlock()
+		return s, nil
+	}
+
+	if pending, ok := i.calls[id]; ok {
+		i.mu.Unlock()
+
+		if err = check(); err != nil {
+			return
+		}
+
+		<-pending.done
+		return pending.service, pending.err
+	}
+
+	pending := &call{done: make(chan struct{})}
+	i.calls[id] = pending
+	i.mu.Unlock()
+
+	completed := false
+
+	defer func() {
+		if !completed {
+			pending.err = fmt.Errorf(`Constructor of "%s" panicked`, id)
+		}
+
+		i.mu.Lock()
+
+		if pending.err == nil {
+			i.services[id] = pending.service
+			i.order = append(i.order, id)
+		}
+
+		delete(i.calls, id)
+		i.mu.Unlock()
+		close(pending.done)
+	}()
+
+	pending.service, pending.err = create()
+	completed = true
+
+	return pending.service, pending.err
+}
+
+// set an instance that was built elsewhere
+func (i *instances) set(id string, service interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.services[id] = service
+	i.order = append(i.order, id)
+}
+
+// len is the number of instances built
+func (i *instances) len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.services)
+}
+
+// clear every instance, returning them with their identifiers in reverse
+// order of creation
+func (i *instances) clear() (ids []string, services []interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for n := len(i.order) - 1; n >= 0; n-- {
+		ids = append(ids, i.order[n])
+		services = append(services, i.services[i.order[n]])
+	}
+
+	i.services = make(map[string]interface{}, 0)
+	i.order = nil
+
+	return
+}
+
+// lookup the instance of id, if it was built
+func (i *instances) lookup(id string) (service interface{}, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	service, ok = i.services[id]
+	return
+}
+
+// remove the instance of id
+func (i *instances) remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if n := indexOf(i.order, id); n >= 0 {
+		i.order = append(i.order[:n], i.order[n+1:]...)
+	}
+
+	delete(i.services, id)
+}
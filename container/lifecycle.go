@@ -0,0 +1,255 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Starter is implemented by services that need to be started along with
+// the container
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by services that need to be stopped when the
+// container shuts down
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+var starterType = reflect.TypeOf((*Starter)(nil)).Elem()
+
+// WithHookTimeout limits the time each start or stop hook may take
+func WithHookTimeout(timeout time.Duration) Option {
+	return func(c *Container) {
+		c.hookTimeout = timeout
+	}
+}
+
+// Start every shared service with start hooks or implementing Starter, after
+// the services it depends on. Services depending on one that failed to start
+// are not started, and every failure is reported in the returned error.
+func (c *Container) Start(ctx context.Context) error {
+	c.mu.RLock()
+	graph, _ := c.graph()
+	order := topological(graph)
+	c.mu.RUnlock()
+
+	var errs []error
+	failed := make(map[string]bool)
+
+	for _, id := range order {
+		if dependsOnAny(graph, id, failed) {
+			failed[id] = true
+			continue
+		}
+
+		c.mu.RLock()
+		def, own := c.definitions[id]
+		started := indexOf(c.started, id) >= 0
+		c.mu.RUnlock()
+
+		if !own || started || def.Scope() != definition.Shared {
+			continue
+		}
+
+		if len(def.StartHooks()) == 0 && !def.Type().Implements(starterType) {
+			continue
+		}
+
+		if err := c.start(ctx, id, def); err != nil {
+			failed[id] = true
+			errs = append(errs, fmt.Errorf(`Could not start service "%s": %w`, id, err))
+			continue
+		}
+
+		c.mu.Lock()
+		c.started = append(c.started, id)
+		c.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the services that were started and the shared services
+// built by the container, before the services they depend on. Services of
+// a parent container are left untouched. Every failure is reported in the
+// returned error. Services built afterwards are new instances.
+func (c *Container) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	graph, _ := c.graph()
+	order := topological(graph)
+	started := c.started
+	c.started = nil
+	c.mu.Unlock()
+
+	var errs []error
+
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+
+		c.mu.RLock()
+		def, own := c.definitions[id]
+		external := c.external[id]
+		c.mu.RUnlock()
+
+		if !own || (external && indexOf(started, id) < 0) {
+			continue
+		}
+
+		service, ok := c.services.lookup(id)
+
+		if !ok {
+			continue
+		}
+
+		if !external {
+			c.services.remove(id)
+		}
+
+		if err := c.stop(ctx, def, service); err != nil {
+			errs = append(errs, fmt.Errorf(`Could not stop service "%s": %w`, id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// start runs the start hooks of the service id, or its Start method when it
+// has none
+func (c *Container) start(ctx context.Context, id string, def definition.Interface) error {
+	service, err := c.Get(id)
+
+	if err != nil {
+		return err
+	}
+
+	if len(def.StartHooks()) == 0 {
+		return c.runHook(ctx, service.(Starter).Start)
+	}
+
+	for _, hook := range def.StartHooks() {
+		if err := c.runHook(ctx, func(ctx context.Context) error { return hook(ctx, service) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stop runs the stop hooks of a service, or its Stop or Close method when it
+// has none
+func (c *Container) stop(ctx context.Context, def definition.Interface, service interface{}) error {
+	if len(def.StopHooks()) == 0 {
+		switch service := service.(type) {
+		case Stopper:
+			return c.runHook(ctx, service.Stop)
+		case io.Closer:
+			return c.runHook(ctx, func(context.Context) error { return service.Close() })
+		}
+
+		return nil
+	}
+
+	var errs []error
+
+	for _, hook := range def.StopHooks() {
+		if err := c.runHook(ctx, func(ctx context.Context) error { return hook(ctx, service) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runHook runs hook, giving up when ctx is done or the hook timeout of the
+// container has elapsed
+func (c *Container) runHook(ctx context.Context, hook func(ctx context.Context) error) error {
+	if c.hookTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.hookTimeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// topological order of the graph, where every identifier comes after the
+// ones it depends on. Identifiers that are part of a cycle are ordered
+// arbitrarily among themselves.
+func topological(graph map[string][]string) (order []string) {
+	ids := make([]string, 0, len(graph))
+
+	for id := range graph {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	visited := make(map[string]bool, len(graph))
+
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+
+		visited[id] = true
+
+		for _, dep := range graph[id] {
+			visit(dep)
+		}
+
+		order = append(order, id)
+	}
+
+	for _, id := range ids {
+		visit(id)
+	}
+
+	return
+}
+
+// dependsOnAny reports whether id depends, directly or not, on any of the
+// identifiers in set
+func dependsOnAny(graph map[string][]string, id string, set map[string]bool) bool {
+	visited := make(map[string]bool)
+
+	var walk func(id string) bool
+	walk = func(id string) bool {
+		for _, dep := range graph[id] {
+			if set[dep] {
+				return true
+			}
+
+			if !visited[dep] {
+				visited[dep] = true
+
+				if walk(dep) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	return walk(id)
+}
@@ -0,0 +1,155 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/drgomesp/cargo/definition"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Events struct{ Log []string }
+
+type Pool struct {
+	Events *Events
+	Fail   error
+}
+
+func (p *Pool) Start(ctx context.Context) error {
+	p.Events.Log = append(p.Events.Log, "start pool")
+	return p.Fail
+}
+
+func (p *Pool) Stop(ctx context.Context) error {
+	p.Events.Log = append(p.Events.Log, "stop pool")
+	return nil
+}
+
+type Server struct{ Pool *Pool }
+
+func (s *Server) Start(ctx context.Context) error {
+	s.Pool.Events.Log = append(s.Pool.Events.Log, "start server")
+	return nil
+}
+
+func (s *Server) Close() error {
+	s.Pool.Events.Log = append(s.Pool.Events.Log, "close server")
+	return nil
+}
+
+func TestStartAndShutdown(t *testing.T) {
+	Convey("Given a server depending on a pool, both with lifecycle methods", t, func() {
+		container := New()
+		events := &Events{}
+		container.Set("events", events)
+		container.Register("server", func(pool *Pool) *Server { return &Server{pool} })
+		container.Register("pool", func(events *Events) *Pool { return &Pool{Events: events} })
+
+		Convey("When the container is started and shut down", func() {
+			startErr := container.Start(context.Background())
+			server := container.MustGet("server")
+			stopErr := container.Shutdown(context.Background())
+
+			Convey("Then the services should be started in dependency order and stopped in reverse", func() {
+				So(startErr, ShouldBeNil)
+				So(stopErr, ShouldBeNil)
+				So(events.Log, ShouldResemble, []string{"start pool", "start server", "close server", "stop pool"})
+			})
+
+			Convey("And the services built by the container should be built again afterwards", func() {
+				So(container.MustGet("server"), ShouldNotPointTo, server)
+			})
+
+			Convey("And the services set on the container should be kept", func() {
+				So(container.MustGet("events"), ShouldPointTo, events)
+			})
+		})
+	})
+}
+
+func TestStartWithFailingService(t *testing.T) {
+	Convey("Given a server depending on a pool that fails to start", t, func() {
+		container := New()
+		events := &Events{}
+		failure := errors.New("no connection")
+		container.Register("server", func(pool *Pool) *Server { return &Server{pool} })
+		container.Register("pool", func() *Pool { return &Pool{Events: events, Fail: failure} })
+
+		Convey("When the container is started", func() {
+			err := container.Start(context.Background())
+
+			Convey("Then it should report the failure", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Could not start service "pool": no connection`)
+				So(errors.Is(err, failure), ShouldBeTrue)
+			})
+
+			Convey("And the services depending on it should not be started", func() {
+				So(events.Log, ShouldResemble, []string{"start pool"})
+			})
+		})
+	})
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	Convey("Given a service with start and stop hooks", t, func() {
+		container := New(WithHookTimeout(20 * time.Millisecond))
+		events := &Events{}
+
+		def, _ := container.Register("pool", func() *Pool { return &Pool{Events: events} })
+		def.OnStart(func(ctx context.Context, service interface{}) error {
+			events.Log = append(events.Log, "start hook")
+			return nil
+		})
+		def.OnStop(func(ctx context.Context, service interface{}) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		def.OnStop(func(ctx context.Context, service interface{}) error {
+			return errors.New("already closed")
+		})
+
+		Convey("When the container is started", func() {
+			err := container.Start(context.Background())
+
+			Convey("Then the hook should replace the Start method of the service", func() {
+				So(err, ShouldBeNil)
+				So(events.Log, ShouldResemble, []string{"start hook"})
+			})
+
+			Convey("And when the container is shut down", func() {
+				err := container.Shutdown(context.Background())
+
+				Convey("Then the stop hooks should time out and every failure should be reported", func() {
+					So(err, ShouldNotBeNil)
+					So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+					So(err.Error(), ShouldContainSubstring, "already closed")
+					So(events.Log, ShouldResemble, []string{"start hook"})
+				})
+			})
+		})
+	})
+}
+
+func TestShutdownSkipsScopedAndExternalServices(t *testing.T) {
+	Convey("Given a scoped closable service and a closable service set on the container", t, func() {
+		container := New()
+		closed := make([]string, 0)
+
+		def, _ := container.Register("scoped", func() *Closable { return &Closable{&closed} })
+		def.SetScope(definition.Scoped)
+		container.Set("external", &Closable{&closed})
+
+		Convey("When the container is shut down", func() {
+			container.NewScope().MustGet("scoped")
+			err := container.Shutdown(context.Background())
+
+			Convey("Then none of them should be closed", func() {
+				So(err, ShouldBeNil)
+				So(closed, ShouldBeEmpty)
+			})
+		})
+	})
+}
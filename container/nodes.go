@@ -0,0 +1,71 @@
+package container
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Node describes a definition of a container along with the services it
+// depends on
+type Node struct {
+	ID           string
+	Type         reflect.Type
+	Scope        definition.Scope
+	Tags         []definition.Tag
+	Aliases      []string
+	Dependencies []string
+	External     bool
+}
+
+// Nodes of the dependency graph of the definitions registered on the
+// container, in lexical order. Dependencies may refer to services of parent
+// containers, and those that cannot be resolved are left out.
+func (c *Container) Nodes() []Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	graph, _ := c.graph()
+	aliases := make(map[string][]string)
+
+	for alias := range c.aliases {
+		if owner, found, _, ok := c.find(alias); ok && owner == c {
+			aliases[found] = append(aliases[found], alias)
+		}
+	}
+
+	nodes := make([]Node, 0, len(c.definitions))
+
+	for _, id := range c.ids() {
+		def := c.definitions[id]
+		sort.Strings(aliases[id])
+
+		nodes = append(nodes, Node{
+			ID:           id,
+			Type:         def.Type(),
+			Scope:        def.Scope(),
+			Tags:         def.Tags(),
+			Aliases:      aliases[id],
+			Dependencies: unique(graph[id]),
+			External:     c.external[id],
+		})
+	}
+
+	return nodes
+}
+
+// unique identifiers of ids in lexical order, leaving empty ones out
+func unique(ids []string) (result []string) {
+	seen := make(map[string]bool)
+
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	sort.Strings(result)
+	return
+}
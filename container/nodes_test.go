@@ -0,0 +1,41 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNodes(t *testing.T) {
+	Convey("Given a container with dependent services", t, func() {
+		container := New()
+		container.Set("logger", &Logger{"app"})
+		container.Alias("log", "logger")
+
+		def, _ := container.Register("repository", func(logger *Logger) *Repository { return &Repository{logger} })
+		def.AddArguments(reference.New("log"))
+		def.AddTag("storage", nil)
+		def.SetScope(definition.Prototype)
+
+		Convey("Then its nodes should describe every definition", func() {
+			So(container.Nodes(), ShouldResemble, []Node{
+				{
+					ID:       "logger",
+					Type:     reflect.TypeOf(&Logger{}),
+					Aliases:  []string{"log"},
+					External: true,
+				},
+				{
+					ID:           "repository",
+					Type:         reflect.TypeOf(&Repository{}),
+					Scope:        definition.Prototype,
+					Tags:         []definition.Tag{{Name: "storage"}},
+					Dependencies: []string{"logger"},
+				},
+			})
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package container
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// SetParameter sets a configuration value that arguments can refer to with
+// %name% placeholders
+func (c *Container) SetParameter(name string, value interface{}) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.mutable(); err != nil {
+		return
+	}
+
+	if name == "" || strings.ContainsRune(name, '%') {
+		err = fmt.Errorf(`Parameter name "%s" is invalid`, name)
+		return
+	}
+
+	c.parameters[name] = value
+	return
+}
+
+// GetParameter gets a configuration value, falling back to the parameters of
+// the parent container
+func (c *Container) GetParameter(name string) (value interface{}, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.parameter(name)
+}
+
+// parameter gets a configuration value. The caller must hold the container
+// lock.
+func (c *Container) parameter(name string) (value interface{}, err error) {
+	for current := c; current != nil; current = current.parent {
+		if value, ok := current.parameters[name]; ok {
+			return value, nil
+		}
+	}
+
+	err = notFoundError(fmt.Sprintf(`No parameter "%s" was found`, name))
+	return
+}
+
+// convert a parameter or literal value to t, when it is not assignable to t but both are
+// strings, or both are numbers and the value survives the conversion
+func convert(value interface{}, t reflect.Type) interface{} {
+	v := reflect.ValueOf(value)
+
+	if value == nil || v.Type().AssignableTo(t) {
+		return value
+	}
+
+	if (numeric(v.Kind()) && numeric(t.Kind()) && lossless(v, t)) || (v.Kind() == reflect.String && t.Kind() == reflect.String) {
+		return v.Convert(t).Interface()
+	}
+
+	return value
+}
+
+// lossless reports whether converting the number v to the numeric type t
+// keeps its value: integers must be within the range of t, and floats
+// converted to integers must have no fractional part
+func lossless(v reflect.Value, t reflect.Type) bool {
+	target := reflect.Zero(t)
+
+	switch {
+	case signed(v.Kind()) && signed(t.Kind()):
+		return !target.OverflowInt(v.Int())
+	case signed(v.Kind()) && unsigned(t.Kind()):
+		return v.Int() >= 0 && !target.OverflowUint(uint64(v.Int()))
+	case unsigned(v.Kind()) && signed(t.Kind()):
+		return v.Uint() <= math.MaxInt64 && !target.OverflowInt(int64(v.Uint()))
+	case unsigned(v.Kind()) && unsigned(t.Kind()):
+		return !target.OverflowUint(v.Uint())
+	case signed(v.Kind()), unsigned(v.Kind()):
+		return v.Convert(t).Convert(v.Type()).Equal(v)
+	}
+
+	f := v.Float()
+
+	switch {
+	case signed(t.Kind()):
+		return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !target.OverflowInt(int64(f))
+	case unsigned(t.Kind()):
+		return f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !target.OverflowUint(uint64(f))
+	default:
+		return !target.OverflowFloat(f)
+	}
+}
+
+func signed(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+func unsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+func numeric(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,219 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/parameter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Database struct {
+	DSN  string
+	Pool int64
+}
+
+func NewDatabase(dsn string, pool int64) *Database {
+	return &Database{dsn, pool}
+}
+
+func TestParameters(t *testing.T) {
+	Convey("Given a container with database parameters", t, func() {
+		container := New()
+		container.SetParameter("db.host", "localhost")
+		container.SetParameter("db.port", 5432)
+		container.SetParameter("db.pool", 10)
+
+		Convey("When getting a parameter", func() {
+			port, err := container.GetParameter("db.port")
+
+			Convey("Then its value should be returned", func() {
+				So(err, ShouldBeNil)
+				So(port, ShouldEqual, 5432)
+			})
+		})
+
+		Convey("When getting an unknown parameter", func() {
+			_, err := container.GetParameter("db.name")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `No parameter "db.name" was found`)
+			})
+		})
+
+		Convey("When setting a parameter with an invalid name", func() {
+			err := container.SetParameter("db%host", "localhost")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Parameter name "db%host" is invalid`)
+			})
+		})
+
+		Convey("When a service is registered with parameter arguments", func() {
+			def, _ := container.Register("db", NewDatabase)
+			def.AddArguments(parameter.New("postgres://%db.host%:%db.port%"), parameter.New("%db.pool%"))
+
+			Convey("Then the parameters should be resolved when building it", func() {
+				db, err := container.Get("db")
+
+				So(err, ShouldBeNil)
+				So(db.(*Database).DSN, ShouldEqual, "postgres://localhost:5432")
+				So(db.(*Database).Pool, ShouldEqual, 10)
+			})
+		})
+
+		Convey("When a service is registered with an unknown parameter", func() {
+			def, _ := container.Register("db", NewDatabase)
+			def.AddArguments(parameter.New("postgres://%db.user%@%db.host%"), parameter.New("%db.pool%"))
+
+			Convey("Then requesting for it should return an error", func() {
+				_, err := container.Get("db")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "db": No parameter "db.user" was found`)
+			})
+
+			Convey("And validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "db": No parameter "db.user" was found`)
+			})
+		})
+
+		Convey("When a child container is created", func() {
+			child := container.NewChild()
+			child.SetParameter("db.host", "replica")
+
+			Convey("Then its parameters should shadow the ones of the parent", func() {
+				host, _ := child.GetParameter("db.host")
+				port, _ := child.GetParameter("db.port")
+				parentHost, _ := container.GetParameter("db.host")
+
+				So(host, ShouldEqual, "replica")
+				So(port, ShouldEqual, 5432)
+				So(parentHost, ShouldEqual, "localhost")
+			})
+		})
+	})
+}
+
+type Listener struct {
+	Port    int64
+	Timeout time.Duration
+}
+
+func NewListener(port int64, timeout time.Duration) *Listener {
+	return &Listener{port, timeout}
+}
+
+func TestEnvParameters(t *testing.T) {
+	t.Setenv("CARGO_PORT", "8080")
+
+	Convey("Given a service with environment variable arguments", t, func() {
+		container := New()
+		def, _ := container.Register("listener", NewListener)
+		def.AddArguments(parameter.Env("int:CARGO_PORT"), parameter.Env("duration:CARGO_TIMEOUT=30s"))
+
+		Convey("Then they should be converted to the types of the constructor parameters", func() {
+			listener, err := container.Get("listener")
+
+			So(err, ShouldBeNil)
+			So(listener.(*Listener).Port, ShouldEqual, 8080)
+			So(listener.(*Listener).Timeout, ShouldEqual, 30*time.Second)
+		})
+	})
+
+	Convey("Given a service with a missing environment variable", t, func() {
+		container := New()
+		def, _ := container.Register("listener", NewListener)
+		def.AddArguments(parameter.Env("int:CARGO_LISTENER_PORT"), parameter.Env("duration:CARGO_TIMEOUT=30s"))
+
+		Convey("Then requesting for it should name the variable and the service", func() {
+			_, err := container.Get("listener")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Argument 0 of "listener": Environment variable "CARGO_LISTENER_PORT" is not set`)
+		})
+
+		Convey("And compiling the container should not read the environment", func() {
+			So(container.Compile(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a service with an invalid environment variable default", t, func() {
+		container := New()
+		def, _ := container.Register("listener", NewListener)
+		def.AddArguments(parameter.Env("int:CARGO_PORT"), parameter.Env("duration:CARGO_TIMEOUT=soon"))
+
+		Convey("Then validating the container should report it", func() {
+			err := container.Validate()
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Argument 1 of "listener": Environment variable "CARGO_TIMEOUT" is not a valid duration: "soon"`)
+		})
+	})
+}
+
+type Sized struct {
+	Workers int
+	Level   uint8
+	Ratio   float32
+}
+
+func NewSized(workers int, level uint8, ratio float32) *Sized {
+	return &Sized{workers, level, ratio}
+}
+
+func TestNumericConversions(t *testing.T) {
+	Convey("Given numbers fitting the types of the constructor parameters", t, func() {
+		container := New()
+		container.SetParameter("workers", 4.0)
+
+		def, _ := container.Register("sized", NewSized)
+		def.AddArguments(parameter.New("%workers%"), argument.New(255), argument.New(0.5))
+
+		Convey("Then they should be converted", func() {
+			So(container.Compile(), ShouldBeNil)
+
+			sized, err := container.Get("sized")
+
+			So(err, ShouldBeNil)
+			So(*sized.(*Sized), ShouldResemble, Sized{4, 255, 0.5})
+		})
+	})
+
+	Convey("Given a fractional parameter for an integer parameter", t, func() {
+		container := New()
+		container.SetParameter("workers", 0.9)
+
+		def, _ := container.Register("sized", NewSized)
+		def.AddArguments(parameter.New("%workers%"), argument.New(1), argument.New(0.5))
+
+		Convey("Then it should not be truncated", func() {
+			_, err := container.Get("sized")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Argument 0 of "sized": Cannot use float64 as int`)
+			So(container.Compile().Error(), ShouldEqual, `Argument 0 of "sized": Cannot use float64 as int`)
+		})
+	})
+
+	Convey("Given an integer out of the range of its parameter", t, func() {
+		container := New()
+
+		def, _ := container.Register("sized", NewSized)
+		def.AddArguments(argument.New(1), argument.New(300), argument.New(0.5))
+
+		Convey("Then it should not overflow", func() {
+			_, err := container.Get("sized")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Argument 1 of "sized": Cannot use int as uint8`)
+			So(container.Compile().Error(), ShouldEqual, `Argument 1 of "sized": Cannot use int as uint8`)
+		})
+	})
+}
@@ -0,0 +1,194 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/method"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+)
+
+// Plan of how a service is built, with every argument resolved statically
+type Plan struct {
+	ID         string
+	Definition definition.Interface
+	Arguments  []Wire
+	Fields     []FieldPlan
+	Calls      []CallPlan
+	Decorators []DecoratorPlan
+}
+
+// Wire is an argument resolved statically, either to the services it refers
+// to or to a value of the parameter type
+type Wire struct {
+	Argument argument.Interface
+	Type     reflect.Type
+	Services []string
+	Tagged   bool
+	Value    reflect.Value
+}
+
+// FieldPlan of a struct field the container injects a service into
+type FieldPlan struct {
+	Field reflect.StructField
+	Wire  Wire
+}
+
+// CallPlan of a method call
+type CallPlan struct {
+	Method    *method.Method
+	Type      reflect.Type
+	Arguments []Wire
+}
+
+// DecoratorPlan of a decorator, whose arguments follow the decorated service
+type DecoratorPlan struct {
+	Definition definition.Interface
+	Arguments  []Wire
+}
+
+// Plan resolves statically how the service id is built, without building
+// any service
+func (c *Container) Plan(id string) (plan Plan, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	owner, found, def, ok := c.find(id)
+
+	if !ok {
+		err = fmt.Errorf(`No service "%s" was found`, id)
+		return
+	}
+
+	plan = Plan{ID: found, Definition: def}
+
+	if def.Template().IsValid() && !owner.external[found] {
+		for _, inject := range injections(def.Type()) {
+			if err = owner.checkInjection(inject); err != nil {
+				return plan, fmt.Errorf(`Field "%s" of "%s": %s`, inject.field.Name, found, err)
+			}
+
+			wire, err := owner.wire(found, inject.arg, inject.field.Type)
+
+			if _, missing := err.(notFoundError); missing && inject.optional {
+				continue
+			}
+
+			if err != nil {
+				return plan, fmt.Errorf(`Field "%s" of "%s": %s`, inject.field.Name, found, err)
+			}
+
+			plan.Fields = append(plan.Fields, FieldPlan{Field: inject.field, Wire: wire})
+		}
+	}
+
+	if constructor := def.Constructor(); constructor.IsValid() {
+		fn := constructor.Type()
+		numIn := fn.NumIn()
+
+		if fn.IsVariadic() {
+			numIn--
+		}
+
+		if !fn.IsVariadic() && len(def.Arguments()) > numIn {
+			return plan, fmt.Errorf(`Constructor of "%s" expects %d arguments`, found, numIn)
+		}
+
+		for i := 0; i < numIn || i < len(def.Arguments()); i++ {
+			var arg argument.Interface
+
+			if i < len(def.Arguments()) {
+				arg = def.Arguments()[i]
+			}
+
+			wire, err := owner.wire(found, arg, parameterType(fn, i))
+
+			if err != nil {
+				return plan, fmt.Errorf(`Argument %d of "%s": %s`, i, found, err)
+			}
+
+			plan.Arguments = append(plan.Arguments, wire)
+		}
+	}
+
+	for _, method := range def.MethodCalls() {
+		fn, err := methodSignature(def.Type(), method)
+
+		if err != nil {
+			return plan, fmt.Errorf(`Method call of "%s": %s`, found, err)
+		}
+
+		call := CallPlan{Method: method, Type: fn}
+
+		for i, arg := range method.Args {
+			wire, err := owner.wire(found, arg, parameterType(fn, i))
+
+			if err != nil {
+				return plan, fmt.Errorf(`Argument %d of method "%s" of "%s": %s`, i, method.Name, found, err)
+			}
+
+			call.Arguments = append(call.Arguments, wire)
+		}
+
+		plan.Calls = append(plan.Calls, call)
+	}
+
+	for i, d := range owner.decorators[found] {
+		fn := d.def.Constructor().Type()
+		decorator := DecoratorPlan{Definition: d.def}
+
+		for j := 1; j < fn.NumIn(); j++ {
+			var arg argument.Interface
+
+			if j <= len(d.def.Arguments()) {
+				arg = d.def.Arguments()[j-1]
+			}
+
+			wire, err := owner.wire(found, arg, fn.In(j))
+
+			if err != nil {
+				return plan, fmt.Errorf(`Argument %d of decorator %d of "%s": %s`, j, i, found, err)
+			}
+
+			decorator.Arguments = append(decorator.Arguments, wire)
+		}
+
+		plan.Decorators = append(plan.Decorators, decorator)
+	}
+
+	return
+}
+
+// wire resolves statically an argument of type t of the service id. The
+// caller must hold the container lock.
+func (c *Container) wire(id string, arg argument.Interface, t reflect.Type) (wire Wire, err error) {
+	wire = Wire{Argument: arg, Type: t}
+
+	if wire.Services, err = c.dependency(id, arg, t); err != nil {
+		return
+	}
+
+	if err = c.checkArgument(arg, t); err != nil {
+		return
+	}
+
+	switch arg := arg.(type) {
+	case nil, reference.Interface:
+	case tagged.Interface:
+		wire.Tagged = true
+	case parameter.Interface:
+		var value interface{}
+
+		if value, err = arg.Check(c.parameter); err == nil {
+			wire.Value, err = argumentValue(convert(value, t), t)
+		}
+	default:
+		wire.Value, err = argumentValue(convert(arg.Value(), t), t)
+	}
+
+	return
+}
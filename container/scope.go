@@ -0,0 +1,131 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Scope holds the instances of scoped services, which are shared by every
+// request made through the same scope. Shared and prototype services are
+// resolved through the container that created the scope.
+type Scope struct {
+	container *Container
+	services  *instances
+	mu        sync.RWMutex
+	disposed  bool
+}
+
+type scopeKey struct{}
+
+// NewScope creates a scope in which scoped services can be resolved
+func (c *Container) NewScope() *Scope {
+	return &Scope{
+		container: c,
+		services:  newInstances(),
+	}
+}
+
+// WithScope returns a copy of ctx carrying the scope
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext returns the scope carried by ctx, if any
+func ScopeFromContext(ctx context.Context) (scope *Scope, ok bool) {
+	scope, ok = ctx.Value(scopeKey{}).(*Scope)
+	return
+}
+
+// GetContext gets a service within the scope carried by ctx. Without a scope
+// created by this container in ctx, it behaves like Get.
+func (c *Container) GetContext(ctx context.Context, id string) (service interface{}, err error) {
+	if scope, ok := ScopeFromContext(ctx); ok && scope.container == c {
+		return scope.Get(id)
+	}
+
+	return c.Get(id)
+}
+
+// Middleware opens a scope for every request, carried by the request
+// context, and disposes of it once the request has been handled. Errors
+// stopping the scoped services are ignored; handlers needing them should
+// manage the scope with NewScope, WithScope and Dispose instead.
+func (c *Container) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := c.NewScope()
+		defer scope.Dispose()
+
+		next.ServeHTTP(w, r.WithContext(WithScope(r.Context(), scope)))
+	})
+}
+
+// Get a service within the scope
+func (s *Scope) Get(id string) (service interface{}, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.disposed {
+		err = fmt.Errorf("Scope has been disposed")
+		return
+	}
+
+	return s.container.get(id, nil, s)
+}
+
+// MustGet is a wrapper for Get that panics if service was not found
+func (s *Scope) MustGet(id string) interface{} {
+	if service, err := s.Get(id); err != nil {
+		panic(err)
+	} else {
+		return service
+	}
+}
+
+// Dispose of the scope, stopping its scoped services in reverse order of
+// creation with their stop hooks, or their Stop or Close methods. The scope
+// cannot be used afterwards.
+func (s *Scope) Dispose() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.disposed {
+		return nil
+	}
+
+	s.disposed = true
+
+	var errs []error
+	ids, services := s.services.clear()
+
+	for i, id := range ids {
+		s.container.mu.RLock()
+		_, def, _ := s.container.definition(id)
+		s.container.mu.RUnlock()
+
+		if err := s.container.stop(context.Background(), def, services[i]); err != nil {
+			errs = append(errs, fmt.Errorf(`Could not stop service "%s": %w`, id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sharedOwner returns the closest shared service in path, which would keep
+// a reference to any scoped service resolved for it
+func (c *Container) sharedOwner(path []string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if _, def, ok := c.definition(path[i]); ok && def.Scope() == definition.Shared {
+			return path[i]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,212 @@
+package container
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drgomesp/cargo/definition"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Transaction struct{ Foo *Foo }
+
+type Handler struct{ Transaction *Transaction }
+
+type Closable struct{ Closed *[]string }
+
+func (c *Closable) Close() error {
+	*c.Closed = append(*c.Closed, "closable")
+	return nil
+}
+
+func TestGetPrototypeService(t *testing.T) {
+	Convey("Given a prototype service", t, func() {
+		container := New()
+		def, _ := container.Register("foo", func() *Foo { return &Foo{} })
+		def.SetScope(definition.Prototype)
+
+		Convey("When requesting for that service twice", func() {
+			first, err := container.Get("foo")
+			second, _ := container.Get("foo")
+
+			Convey("Then a new instance should be returned each time", func() {
+				So(err, ShouldBeNil)
+				So(first, ShouldNotPointTo, second)
+			})
+		})
+	})
+}
+
+func TestGetScopedService(t *testing.T) {
+	Convey("Given a scoped service depending on a shared one", t, func() {
+		container := New()
+		container.Register("foo", func() *Foo { return &Foo{} })
+
+		def, _ := container.Register("tx", func(foo *Foo) *Transaction { return &Transaction{foo} })
+		def.SetScope(definition.Scoped)
+
+		Convey("When requesting for it outside of a scope", func() {
+			_, err := container.Get("tx")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Scoped service "tx" must be resolved within a scope`)
+			})
+		})
+
+		Convey("When requesting for it within scopes", func() {
+			first := container.NewScope()
+			second := container.NewScope()
+
+			a, err := first.Get("tx")
+			b, _ := first.Get("tx")
+			c, _ := second.Get("tx")
+
+			Convey("Then one instance should be built per scope", func() {
+				So(err, ShouldBeNil)
+				So(a, ShouldPointTo, b)
+				So(a, ShouldNotPointTo, c)
+			})
+
+			Convey("And the shared dependency should be the same in every scope", func() {
+				So(a.(*Transaction).Foo, ShouldPointTo, c.(*Transaction).Foo)
+				So(a.(*Transaction).Foo, ShouldPointTo, container.MustGet("foo"))
+			})
+		})
+	})
+}
+
+func TestSharedServiceCannotCaptureScopedService(t *testing.T) {
+	Convey("Given a shared service depending on a scoped one through a prototype", t, func() {
+		container := New()
+		container.Register("foo", func() *Foo { return &Foo{} })
+
+		tx, _ := container.Register("tx", func(foo *Foo) *Transaction { return &Transaction{foo} })
+		tx.SetScope(definition.Scoped)
+
+		proxy, _ := container.Register("proxy", func(tx *Transaction) *A { return &A{} })
+		proxy.SetScope(definition.Prototype)
+
+		container.Register("handler", func(a *A) *Handler { return &Handler{} })
+
+		Convey("When requesting for the shared service within a scope", func() {
+			_, err := container.NewScope().Get("handler")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "handler": Argument 0 of "proxy": Shared service "handler" cannot depend on scoped service "tx"`)
+			})
+		})
+
+		Convey("When validating the container", func() {
+			err := container.Validate()
+
+			Convey("Then it should report the captured scoped service", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Shared service "handler" cannot depend on scoped service "tx"`)
+			})
+		})
+
+		Convey("When the prototype is requested within a scope", func() {
+			proxy, err := container.NewScope().Get("proxy")
+
+			Convey("Then it should be built with the scoped dependency", func() {
+				So(err, ShouldBeNil)
+				So(proxy, ShouldHaveSameTypeAs, &A{})
+			})
+		})
+	})
+}
+
+func TestGetContext(t *testing.T) {
+	Convey("Given a scoped service", t, func() {
+		container := New()
+		container.Register("foo", func() *Foo { return &Foo{} })
+
+		def, _ := container.Register("tx", func(foo *Foo) *Transaction { return &Transaction{foo} })
+		def.SetScope(definition.Scoped)
+
+		Convey("When requesting for it with a context carrying a scope", func() {
+			scope := container.NewScope()
+			ctx := WithScope(context.Background(), scope)
+
+			tx, err := container.GetContext(ctx, "tx")
+
+			Convey("Then it should be resolved within that scope", func() {
+				So(err, ShouldBeNil)
+				So(tx, ShouldPointTo, scope.MustGet("tx"))
+			})
+		})
+
+		Convey("When requesting for it with a context without a scope", func() {
+			_, err := container.GetContext(context.Background(), "tx")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Scoped service "tx" must be resolved within a scope`)
+			})
+		})
+	})
+}
+
+func TestDisposeScope(t *testing.T) {
+	Convey("Given a scoped service implementing io.Closer", t, func() {
+		container := New()
+		closed := make([]string, 0)
+
+		def, _ := container.Register("closable", func() *Closable { return &Closable{&closed} })
+		def.SetScope(definition.Scoped)
+
+		Convey("When a scope that built it is disposed", func() {
+			scope := container.NewScope()
+			scope.MustGet("closable")
+			err := scope.Dispose()
+
+			Convey("Then the service should have been closed", func() {
+				So(err, ShouldBeNil)
+				So(closed, ShouldResemble, []string{"closable"})
+			})
+
+			Convey("And the scope should not be usable anymore", func() {
+				_, err := scope.Get("closable")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Scope has been disposed")
+			})
+		})
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a scoped service and an HTTP handler wrapped by the container middleware", t, func() {
+		container := New()
+		closed := make([]string, 0)
+
+		def, _ := container.Register("closable", func() *Closable { return &Closable{&closed} })
+		def.SetScope(definition.Scoped)
+
+		instances := make([]interface{}, 0)
+		handler := container.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			first, _ := container.GetContext(r.Context(), "closable")
+			second, _ := container.GetContext(r.Context(), "closable")
+
+			So(first, ShouldPointTo, second)
+			instances = append(instances, first)
+		}))
+
+		Convey("When two requests are served", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+			Convey("Then each request should get its own instance", func() {
+				So(instances, ShouldHaveLength, 2)
+				So(instances[0], ShouldNotPointTo, instances[1])
+			})
+
+			Convey("And the scope of each request should have been disposed", func() {
+				So(closed, ShouldHaveLength, 2)
+			})
+		})
+	})
+}
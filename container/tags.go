@@ -0,0 +1,118 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FindTagged returns the identifiers of every service tagged with tag, in the
+// container or its ancestors, by decreasing priority attribute and then by
+// identifier
+func (c *Container) FindTagged(tag string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.findTagged(tag)
+}
+
+// findTagged is FindTagged for callers already holding the container lock
+func (c *Container) findTagged(tag string) []string {
+	priorities := make(map[string]float64)
+	shadowed := make(map[string]bool)
+
+	for current := c; current != nil; current = current.parent {
+		for id, def := range current.definitions {
+			if shadowed[id] {
+				continue
+			}
+
+			shadowed[id] = true
+
+			for _, t := range def.Tags() {
+				if t.Name == tag {
+					priorities[id] = priority(t.Attributes["priority"])
+					break
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(priorities))
+
+	for id := range priorities {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if priorities[ids[i]] != priorities[ids[j]] {
+			return priorities[ids[i]] > priorities[ids[j]]
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// priority attribute of a tag, where anything but a number counts as zero
+func priority(value interface{}) float64 {
+	v := reflect.ValueOf(value)
+
+	if !v.IsValid() || !numeric(v.Kind()) {
+		return 0
+	}
+
+	return v.Convert(reflect.TypeOf(float64(0))).Float()
+}
+
+// collect builds the services tagged with tag into a slice, or a map keyed
+// by identifier, of type t
+func (c *Container) collect(tag string, t reflect.Type, path []string, scope *Scope) (value interface{}, err error) {
+	if err = checkCollection(tag, t); err != nil {
+		return
+	}
+
+	c.mu.RLock()
+	ids := c.findTagged(tag)
+	c.mu.RUnlock()
+
+	collection := reflect.MakeSlice(reflect.SliceOf(t.Elem()), 0, len(ids))
+
+	if t.Kind() == reflect.Map {
+		collection = reflect.MakeMapWithSize(t, len(ids))
+	}
+
+	for _, id := range ids {
+		var service interface{}
+
+		if service, err = c.get(id, path, scope); err != nil {
+			return
+		}
+
+		v := reflect.ValueOf(service)
+
+		if !v.IsValid() || !v.Type().AssignableTo(t.Elem()) {
+			err = fmt.Errorf(`Cannot use "%s" as %s`, id, t.Elem())
+			return
+		}
+
+		if t.Kind() == reflect.Map {
+			collection.SetMapIndex(reflect.ValueOf(id).Convert(t.Key()), v)
+		} else {
+			collection = reflect.Append(collection, v)
+		}
+	}
+
+	return collection.Convert(t).Interface(), nil
+}
+
+// checkCollection checks that services tagged with tag can be collected into
+// a value of type t
+func checkCollection(tag string, t reflect.Type) error {
+	if t.Kind() == reflect.Slice || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String) {
+		return nil
+	}
+
+	return fmt.Errorf(`Cannot collect services tagged "%s" as %s`, tag, t)
+}
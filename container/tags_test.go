@@ -0,0 +1,125 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/drgomesp/cargo/tagged"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Check interface {
+	Check() error
+}
+
+type Ping struct{ Name string }
+
+func (p *Ping) Check() error {
+	return nil
+}
+
+type Health struct{ Checks []Check }
+
+type Router struct{ Routes map[string]Check }
+
+func TestFindTagged(t *testing.T) {
+	Convey("Given a container with tagged services", t, func() {
+		container := New()
+
+		db, _ := container.Register("db", &Ping{Name: "db"})
+		db.AddTag("health", map[string]interface{}{"priority": 10})
+
+		cache, _ := container.Register("cache", &Ping{Name: "cache"})
+		cache.AddTag("health", nil)
+
+		queue, _ := container.Register("queue", &Ping{Name: "queue"})
+		queue.AddTag("health", map[string]interface{}{"priority": 20.5})
+
+		container.Register("mail", &Ping{Name: "mail"})
+
+		Convey("Then they should be found by decreasing priority", func() {
+			So(container.FindTagged("health"), ShouldResemble, []string{"queue", "db", "cache"})
+			So(container.FindTagged("route"), ShouldBeEmpty)
+		})
+
+		Convey("When a child container shadows a tagged service", func() {
+			child := container.NewChild()
+			child.Register("db", &Ping{Name: "replica"})
+
+			Convey("Then the untagged definition of the child should hide it", func() {
+				So(child.FindTagged("health"), ShouldResemble, []string{"queue", "cache"})
+			})
+		})
+
+		Convey("When a service is registered with a tagged slice argument", func() {
+			def, _ := container.Register("health", func(checks []Check) *Health {
+				return &Health{checks}
+			})
+			def.AddArguments(tagged.New("health"))
+
+			Convey("Then every tagged service should be injected in order", func() {
+				health, err := container.Get("health")
+
+				So(err, ShouldBeNil)
+				So(health.(*Health).Checks, ShouldResemble, []Check{
+					container.MustGet("queue").(Check),
+					container.MustGet("db").(Check),
+					container.MustGet("cache").(Check),
+				})
+				So(container.Validate(), ShouldBeNil)
+			})
+		})
+
+		Convey("When a service is registered with a tagged map argument", func() {
+			def, _ := container.Register("router", func(routes map[string]Check) *Router {
+				return &Router{routes}
+			})
+			def.AddArguments(tagged.New("health"))
+
+			Convey("Then every tagged service should be injected by identifier", func() {
+				router, err := container.Get("router")
+
+				So(err, ShouldBeNil)
+				So(router.(*Router).Routes, ShouldHaveLength, 3)
+				So(router.(*Router).Routes["db"], ShouldEqual, container.MustGet("db"))
+			})
+		})
+
+		Convey("When a tagged service cannot be used as the element type", func() {
+			logger, _ := container.Register("logger", &Logger{})
+			logger.AddTag("health", nil)
+
+			def, _ := container.Register("health", func(checks []Check) *Health {
+				return &Health{checks}
+			})
+			def.AddArguments(tagged.New("health"))
+
+			Convey("Then requesting for the consumer should return an error", func() {
+				_, err := container.Get("health")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "health": Cannot use "logger" as container.Check`)
+			})
+
+			Convey("And validating the container should report it", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "health": Cannot use "logger" as container.Check`)
+			})
+		})
+
+		Convey("When tagged services are injected as neither a slice nor a map", func() {
+			def, _ := container.Register("health", func(check Check) *Health {
+				return &Health{[]Check{check}}
+			})
+			def.AddArguments(tagged.New("health"))
+
+			Convey("Then requesting for the consumer should return an error", func() {
+				_, err := container.Get("health")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Argument 0 of "health": Cannot collect services tagged "health" as container.Check`)
+			})
+		})
+	})
+}
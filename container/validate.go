@@ -0,0 +1,424 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+)
+
+// Validate the wiring of every definition without building any service. It
+// reports references to unknown services and parameters, arguments that
+// cannot be autowired or are not assignable to their parameters, invalid
+// aliases and bindings, shared services depending on scoped ones and every
+// circular dependency in the container.
+func (c *Container) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.validate()
+}
+
+// validate is Validate for callers already holding the container lock
+func (c *Container) validate() error {
+	graph, errs := c.graph()
+	errs = append(errs, c.checkAliases()...)
+
+	for _, id := range c.ids() {
+		if c.definitions[id].Scope() != definition.Shared {
+			continue
+		}
+
+		if scoped := c.captive(graph, id); scoped != "" {
+			errs = append(errs, fmt.Errorf(`Shared service "%s" cannot depend on scoped service "%s"`, id, scoped))
+		}
+	}
+
+	for _, cycle := range Cycles(graph) {
+		errs = append(errs, &CircularDependencyError{Path: cycle})
+	}
+
+	return errors.Join(errs...)
+}
+
+// graph of the dependencies between every definition, along with the errors
+// found while resolving them statically. The caller must hold the container
+// lock.
+func (c *Container) graph() (graph map[string][]string, errs []error) {
+	graph = make(map[string][]string, len(c.definitions))
+
+	for _, id := range c.ids() {
+		deps, depErrs := c.dependencies(id, c.definitions[id])
+		graph[id] = deps
+		errs = append(errs, depErrs...)
+	}
+
+	return
+}
+
+// ids of every definition in lexical order. The caller must hold the
+// container lock.
+func (c *Container) ids() []string {
+	ids := make([]string, 0, len(c.definitions))
+
+	for id := range c.definitions {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// dependencies returns the identifiers of the services the definition id and
+// its decorators depend on, along with the errors found while resolving them
+// statically. The caller must hold the container lock.
+func (c *Container) dependencies(id string, def definition.Interface) (deps []string, errs []error) {
+	deps, errs = c.definitionDependencies(id, def)
+	decoratorDeps, decoratorErrs := c.decoratorDependencies(id)
+
+	return append(deps, decoratorDeps...), append(errs, decoratorErrs...)
+}
+
+// definitionDependencies is dependencies, leaving decorators out
+func (c *Container) definitionDependencies(id string, def definition.Interface) (deps []string, errs []error) {
+	if owner, found, _, ok := c.find(id); ok && owner.external[found] {
+		return
+	}
+
+	if def.Template().IsValid() {
+		for _, inject := range injections(def.Type()) {
+			found, err := c.dependency(id, inject.arg, inject.field.Type)
+
+			if err == nil {
+				err = c.checkInjection(inject)
+			}
+
+			if err == nil {
+				err = c.checkArgument(inject.arg, inject.field.Type)
+			}
+
+			if _, missing := err.(notFoundError); missing && inject.optional {
+				continue
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf(`Field "%s" of "%s": %s`, inject.field.Name, id, err))
+			} else {
+				deps = append(deps, found...)
+			}
+		}
+	}
+
+	constructor := def.Constructor()
+
+	if !constructor.IsValid() {
+		return
+	}
+
+	fn := constructor.Type()
+	numIn := fn.NumIn()
+
+	if fn.IsVariadic() {
+		numIn--
+	}
+
+	if !fn.IsVariadic() && len(def.Arguments()) > numIn {
+		errs = append(errs, fmt.Errorf(`Constructor of "%s" expects %d arguments`, id, numIn))
+		return
+	}
+
+	for i := 0; i < numIn || i < len(def.Arguments()); i++ {
+		var arg argument.Interface
+
+		if i < len(def.Arguments()) {
+			arg = def.Arguments()[i]
+		}
+
+		t := parameterType(fn, i)
+		found, err := c.dependency(id, arg, t)
+
+		if err == nil {
+			err = c.checkArgument(arg, t)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`Argument %d of "%s": %s`, i, id, err))
+		} else {
+			deps = append(deps, found...)
+		}
+	}
+
+	for _, method := range def.MethodCalls() {
+		fn, err := methodSignature(def.Type(), method)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`Method call of "%s": %s`, id, err))
+			continue
+		}
+
+		for i, arg := range method.Args {
+			t := parameterType(fn, i)
+			found, err := c.dependency(id, arg, t)
+
+			if err == nil {
+				err = c.checkArgument(arg, t)
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf(`Argument %d of method "%s" of "%s": %s`, i, method.Name, id, err))
+			} else {
+				deps = append(deps, found...)
+			}
+		}
+	}
+
+	return
+}
+
+// dependency returns the identifiers of the services an argument of type t
+// of the service id refers to, which are none for literal arguments. The
+// caller must hold the container lock.
+func (c *Container) dependency(id string, arg argument.Interface, t reflect.Type) (deps []string, err error) {
+	switch arg := arg.(type) {
+	case nil:
+		found, err := c.lookup(t, id)
+
+		return []string{found}, err
+	case reference.Interface:
+		found, _, ok := c.definition(arg.Identifier())
+
+		if !ok {
+			err = notFoundError(fmt.Sprintf(`No service "%s" was found`, arg.Identifier()))
+		}
+
+		return []string{found}, err
+	case parameter.Interface:
+		_, err = arg.Check(c.parameter)
+	case tagged.Interface:
+		if err = checkCollection(arg.Tag(), t); err != nil {
+			return
+		}
+
+		for _, found := range c.findTagged(arg.Tag()) {
+			if _, def, _ := c.definition(found); !def.Type().AssignableTo(t.Elem()) && def.Type().Kind() != reflect.Interface {
+				err = fmt.Errorf(`Cannot use "%s" as %s`, found, t.Elem())
+				return
+			}
+
+			deps = append(deps, found)
+		}
+	}
+
+	return
+}
+
+// checkArgument checks that an argument can be used as a value of type t
+// without building any service. The caller must hold the container lock.
+func (c *Container) checkArgument(arg argument.Interface, t reflect.Type) (err error) {
+	switch arg := arg.(type) {
+	case nil, tagged.Interface:
+	case parameter.Interface:
+		var value interface{}
+
+		if value, err = arg.Check(c.parameter); err == nil {
+			_, err = argumentValue(convert(value, t), t)
+		}
+	case reference.Interface:
+		if _, def, ok := c.definition(arg.Identifier()); ok && !def.Type().AssignableTo(t) && def.Type().Kind() != reflect.Interface {
+			err = fmt.Errorf("Cannot use %s as %s", def.Type(), t)
+		}
+	default:
+		_, err = argumentValue(convert(arg.Value(), t), t)
+	}
+
+	return
+}
+
+// methodType returns the signature of the method name of t, without its
+// receiver
+func methodType(t reflect.Type, name string) (fn reflect.Type, ok bool) {
+	m, ok := t.MethodByName(name)
+
+	if !ok || t.Kind() == reflect.Interface {
+		return m.Type, ok
+	}
+
+	in := make([]reflect.Type, m.Type.NumIn()-1)
+
+	for i := range in {
+		in[i] = m.Type.In(i + 1)
+	}
+
+	out := make([]reflect.Type, m.Type.NumOut())
+
+	for i := range out {
+		out[i] = m.Type.Out(i)
+	}
+
+	return reflect.FuncOf(in, out, m.Type.IsVariadic()), true
+}
+
+// captive returns the first scoped service the shared service id would keep
+// a reference to, either directly or through prototype services. The caller
+// must hold the container lock.
+func (c *Container) captive(graph map[string][]string, id string) string {
+	visited := make(map[string]bool)
+
+	var walk func(id string) string
+	walk = func(id string) string {
+		for _, dep := range graph[id] {
+			if visited[dep] {
+				continue
+			}
+
+			visited[dep] = true
+
+			_, def, _ := c.definition(dep)
+
+			switch def.Scope() {
+			case definition.Scoped:
+				return dep
+			case definition.Prototype:
+				if scoped := walk(dep); scoped != "" {
+					return scoped
+				}
+			}
+		}
+
+		return ""
+	}
+
+	return walk(id)
+}
+
+// pathTo returns the dependency chain leading from id to any of the
+// identifiers in targets, or nil when none of them can be reached. The caller
+// must hold the container lock.
+func (c *Container) pathTo(id string, targets []string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(id string, chain []string) []string
+	walk = func(id string, chain []string) []string {
+		chain = append(chain[:len(chain):len(chain)], id)
+
+		if indexOf(targets, id) >= 0 {
+			return chain
+		}
+
+		_, def, ok := c.definition(id)
+
+		if visited[id] || !ok {
+			return nil
+		}
+
+		visited[id] = true
+		deps, _ := c.dependencies(id, def)
+
+		for _, dep := range deps {
+			if found := walk(dep, chain); found != nil {
+				return found
+			}
+		}
+
+		return nil
+	}
+
+	return walk(id, nil)
+}
+
+// Cycles finds every elementary circular dependency in a graph mapping
+// identifiers to the identifiers they depend on, with Johnson's algorithm.
+// Each cycle is reported once, starting and ending at its lexically smallest
+// identifier, and cycles sharing identifiers are all reported.
+func Cycles(graph map[string][]string) (found [][]string) {
+	ids := make([]string, 0, len(graph))
+	deps := make(map[string][]string, len(graph))
+
+	for id, edges := range graph {
+		ids = append(ids, id)
+
+		for _, dep := range edges {
+			if _, ok := graph[dep]; ok && indexOf(deps[id], dep) < 0 {
+				deps[id] = append(deps[id], dep)
+			}
+		}
+
+		sort.Strings(deps[id])
+	}
+
+	sort.Strings(ids)
+
+	for _, start := range ids {
+		blocked := make(map[string]bool)
+		blockers := make(map[string]map[string]bool)
+		stack := make([]string, 0)
+
+		var unblock func(id string)
+		unblock = func(id string) {
+			blocked[id] = false
+
+			for blocker := range blockers[id] {
+				delete(blockers[id], blocker)
+
+				if blocked[blocker] {
+					unblock(blocker)
+				}
+			}
+		}
+
+		// circuit finds the cycles through start continuing from id, only
+		// visiting identifiers after start so that each cycle is found once
+		var circuit func(id string) bool
+		circuit = func(id string) (closed bool) {
+			stack = append(stack, id)
+			blocked[id] = true
+
+			for _, dep := range deps[id] {
+				switch {
+				case dep == start:
+					found = append(found, append(append([]string(nil), stack...), start))
+					closed = true
+				case dep > start && !blocked[dep]:
+					closed = circuit(dep) || closed
+				}
+			}
+
+			if closed {
+				unblock(id)
+			} else {
+				for _, dep := range deps[id] {
+					if dep > start {
+						if blockers[dep] == nil {
+							blockers[dep] = make(map[string]bool)
+						}
+
+						blockers[dep][id] = true
+					}
+				}
+			}
+
+			stack = stack[:len(stack)-1]
+			return
+		}
+
+		circuit(start)
+	}
+
+	return
+}
+
+func indexOf(ids []string, id string) int {
+	for i, candidate := range ids {
+		if candidate == id {
+			return i
+		}
+	}
+
+	return -1
+}
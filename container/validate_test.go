@@ -0,0 +1,236 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drgomesp/cargo/method"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type A struct{ B *B }
+type B struct{ A *A }
+type Self struct{ Self *Self }
+
+func TestGetServiceWithCircularDependency(t *testing.T) {
+	Convey(`Given a service container where "a" depends on "b" and "b" depends on "a"`, t, func() {
+		container := New()
+
+		a, _ := container.Register("a", func(b *B) *A { return &A{b} })
+		refB := reference.New("b")
+		a.AddArguments(&refB)
+
+		b, _ := container.Register("b", func(a *A) *B { return &B{a} })
+		refA := reference.New("a")
+		b.AddArguments(&refA)
+
+		Convey(`When requesting for the "a" service`, func() {
+			_, err := container.Get("a")
+
+			Convey("Then it should return the cycle instead of overflowing the stack", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: a -> b -> a")
+
+				var cycle *CircularDependencyError
+				So(errors.As(err, &cycle), ShouldBeTrue)
+				So(cycle.Path, ShouldResemble, []string{"a", "b", "a"})
+			})
+		})
+
+		Convey("When validating the container", func() {
+			err := container.Validate()
+
+			Convey("Then it should report the cycle without building any service", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: a -> b -> a")
+				So(container.services.len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+
+		Convey("And a service depending on itself plus a reference to an unknown service", func() {
+			c, _ := container.Register("c", func(s *Self) *Self { return &Self{s} })
+			refC := reference.New("c")
+			c.AddArguments(&refC)
+
+			b, _ := container.Register("b", func(a *A) *B { return &B{a} })
+			refMissing := reference.New("missing")
+			b.AddArguments(&refMissing)
+
+			Convey("When validating the container", func() {
+				err := container.Validate()
+
+				Convey("Then it should report every problem", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Argument 0 of \"b\": No service \"missing\" was found\nCircular dependency: c -> c")
+				})
+			})
+		})
+
+		Convey("And cycles sharing a service", func() {
+			a, _ := container.Register("a", func(b, c *Foo) *Foo { return &Foo{} })
+			a.AddArguments(reference.New("b"), reference.New("c"))
+
+			for _, id := range []string{"b", "c"} {
+				def, _ := container.Register(id, func(d *Foo) *Foo { return &Foo{} })
+				def.AddArguments(reference.New("d"))
+			}
+
+			d, _ := container.Register("d", func(a *Foo) *Foo { return &Foo{} })
+			d.AddArguments(reference.New("a"))
+
+			Convey("Then validating the container should report each of them", func() {
+				err := container.Validate()
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Circular dependency: a -> b -> d -> a\nCircular dependency: a -> c -> d -> a")
+			})
+		})
+
+		Convey("And services whose dependencies are all satisfied", func() {
+			container.Set("foo", &Foo{})
+			container.Register("bar", func(foo *Foo) *B { return &B{} })
+
+			Convey("Then validating the container should return an empty error", func() {
+				So(container.Validate(), ShouldBeNil)
+			})
+		})
+
+		Convey("And a function set as an instance", func() {
+			greet := func(name string) string { return "Hello " + name }
+			container.Set("greet", greet)
+
+			Convey("Then it should not be validated as a constructor", func() {
+				So(container.Validate(), ShouldBeNil)
+				So(container.Compile(), ShouldBeNil)
+			})
+
+			Convey("And its type should be the type of the function", func() {
+				def, _ := container.Definition("greet")
+
+				So(def.Type(), ShouldEqual, reflect.TypeOf(greet))
+			})
+		})
+	})
+}
+
+type Connection struct{ Opened bool }
+
+func (c *Connection) Open(dsn string) error {
+	if dsn == "" {
+		return errors.New("empty dsn")
+	}
+
+	c.Opened = true
+	return nil
+}
+
+func (c *Connection) reset() {}
+
+func TestGetServiceWithInvalidMethodCalls(t *testing.T) {
+	Convey("Given a service container instance", t, func() {
+		container := New()
+		def, _ := container.Register("connection", func() *Connection { return &Connection{} })
+
+		Convey("When a method call refers to a method that does not exist", func() {
+			def.AddMethodCall(method.New("Close"))
+
+			Convey("Then requesting for the service should return an error", func() {
+				_, err := container.Get("connection")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Method "Close" does not exist on *container.Connection`)
+			})
+		})
+
+		Convey("When a method call refers to an unexported method", func() {
+			def.AddMethodCall(method.New("reset"))
+
+			Convey("Then requesting for the service should return an error", func() {
+				_, err := container.Get("connection")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Method "reset" of *container.Connection is not exported`)
+			})
+		})
+
+		Convey("When a called method returns an error", func() {
+			def.AddMethodCall(method.New("Open", ""))
+
+			Convey("Then requesting for the service should return that error", func() {
+				_, err := container.Get("connection")
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Could not create service "connection" (connection): Method "Open": empty dsn`)
+			})
+		})
+
+		Convey("When a called method succeeds", func() {
+			def.AddMethodCall(method.New("Open", "postgres://localhost"))
+
+			Convey("Then the service should be returned", func() {
+				connection, err := container.Get("connection")
+
+				So(err, ShouldBeNil)
+				So(connection.(*Connection).Opened, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestValidateMethodCalls(t *testing.T) {
+	Convey("Given a service with invalid method calls", t, func() {
+		container := New()
+		container.Set("foo", &Foo{})
+
+		def, _ := container.Register("connection", func() *Connection { return &Connection{} })
+		def.AddMethodCall(method.New("Close"))
+		def.AddMethodCall(method.New("reset"))
+		def.AddMethodCall(method.New("Open"))
+		def.AddMethodCall(method.New("Open", 10))
+		def.AddMethodCall(method.New("Open", reference.New("foo")))
+
+		Convey("When validating the container", func() {
+			err := container.Validate()
+
+			Convey("Then every invalid method call should be reported", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, strings.Join([]string{
+					`Method call of "connection": Method "Close" does not exist on *container.Connection`,
+					`Method call of "connection": Method "reset" of *container.Connection is not exported`,
+					`Method call of "connection": Method "Open" expects 1 arguments`,
+					`Argument 0 of method "Open" of "connection": Cannot use int as string`,
+					`Argument 0 of method "Open" of "connection": Cannot use *container.Foo as string`,
+				}, "\n"))
+			})
+		})
+	})
+}
+
+func TestCycles(t *testing.T) {
+	Convey("Given a graph with overlapping cycles, a self dependency and missing nodes", t, func() {
+		graph := map[string][]string{
+			"a": {"b", "c", "missing"},
+			"b": {"a", "c", "c"},
+			"c": {"a"},
+			"d": {"d"},
+		}
+
+		Convey("Then every elementary cycle should be found once", func() {
+			So(Cycles(graph), ShouldResemble, [][]string{
+				{"a", "b", "a"},
+				{"a", "b", "c", "a"},
+				{"a", "c", "a"},
+				{"d", "d"},
+			})
+		})
+	})
+}
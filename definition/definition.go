@@ -1,6 +1,7 @@
 package definition
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -8,30 +9,43 @@ import (
 	"github.com/drgomesp/cargo/method"
 )
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Hook run on a service instance when the container starts or stops
+type Hook func(ctx context.Context, service interface{}) error
+
 // Definition of a service or an argument
 type Definition struct {
 	arguments   []argument.Interface
 	methodCalls []*method.Method
 	constructor reflect.Value
+	template    reflect.Value
 	t           reflect.Type
+	scope       Scope
+	startHooks  []Hook
+	stopHooks   []Hook
+	tags        []Tag
 }
 
 // New definition based on factory functions or pointers
 func New(arg interface{}, args ...interface{}) (def Interface, err error) {
 	switch reflect.TypeOf(arg).Kind() {
 	case reflect.Func:
-		if reflect.TypeOf(arg).NumOut() == 0 {
+		fn := reflect.TypeOf(arg)
+
+		if fn.NumOut() == 0 {
 			err = fmt.Errorf("Constructor function must have a return type")
 			return
 		}
 
-		if constructor, err := createFromConstructorFunction(reflect.ValueOf(arg)); nil == err {
-			def = constructor
+		if fn.NumOut() > 2 || (fn.NumOut() == 2 && fn.Out(1) != errorType) {
+			err = fmt.Errorf("Constructor function must return a single value and an optional error")
+			return
 		}
+
+		def, err = createFromConstructorFunction(reflect.ValueOf(arg))
 	case reflect.Ptr:
-		if constructor, err := createFromPointer(&arg); nil == err {
-			def = constructor
-		}
+		def, err = createFromPointer(arg)
 	default:
 		err = fmt.Errorf("A definition must be created from a pointer to a struct or a constructor function")
 	}
@@ -39,6 +53,23 @@ func New(arg interface{}, args ...interface{}) (def Interface, err error) {
 	return
 }
 
+// NewInstance definition of a service that already exists, from the same
+// pointers or functions as New. Its type is the type of the instance itself
+// and it has neither constructor nor template.
+func NewInstance(instance interface{}) (def Interface, err error) {
+	if _, err = New(instance); err != nil {
+		return
+	}
+
+	def = &Definition{
+		arguments:   make([]argument.Interface, 0),
+		methodCalls: make([]*method.Method, 0),
+		t:           reflect.TypeOf(instance),
+	}
+
+	return
+}
+
 // AddArguments to the definition
 func (d *Definition) AddArguments(arg ...argument.Interface) Interface {
 	d.arguments = append(d.arguments, arg...)
@@ -51,6 +82,32 @@ func (d *Definition) AddMethodCall(method *method.Method) Interface {
 	return Interface(d)
 }
 
+// SetScope defines the lifetime of the instances of the service
+func (d *Definition) SetScope(scope Scope) Interface {
+	d.scope = scope
+	return Interface(d)
+}
+
+// OnStart adds a hook to run when the container starts, replacing the
+// detection of a Start method on the service
+func (d *Definition) OnStart(hook Hook) Interface {
+	d.startHooks = append(d.startHooks, hook)
+	return Interface(d)
+}
+
+// OnStop adds a hook to run when the container shuts down, replacing the
+// detection of a Stop or Close method on the service
+func (d *Definition) OnStop(hook Hook) Interface {
+	d.stopHooks = append(d.stopHooks, hook)
+	return Interface(d)
+}
+
+// AddTag to the definition
+func (d *Definition) AddTag(name string, attributes map[string]interface{}) Interface {
+	d.tags = append(d.tags, Tag{Name: name, Attributes: attributes})
+	return Interface(d)
+}
+
 // Arguments of the definition
 func (d *Definition) Arguments() []argument.Interface {
 	return d.arguments
@@ -66,25 +123,43 @@ func (d *Definition) Constructor() reflect.Value {
 	return d.constructor
 }
 
+// Template of the definition, which is the pointer it was created from. The
+// services of a pointer definition are copies of the value it points to.
+func (d *Definition) Template() reflect.Value {
+	return d.template
+}
+
 // Type for the definition
 func (d *Definition) Type() reflect.Type {
 	return d.t
 }
 
-func createFromConstructorFunction(fn reflect.Value) (def Interface, err error) {
-	var returnType reflect.Type
+// Scope of the definition
+func (d *Definition) Scope() Scope {
+	return d.scope
+}
 
-	constructor := reflect.MakeFunc(fn.Type(), func(in []reflect.Value) []reflect.Value {
-		returnType = reflect.TypeOf(fn.Interface()).Out(0)
+// StartHooks of the definition
+func (d *Definition) StartHooks() []Hook {
+	return d.startHooks
+}
 
-		return []reflect.Value{reflect.New(returnType.Elem())}
-	})
+// StopHooks of the definition
+func (d *Definition) StopHooks() []Hook {
+	return d.stopHooks
+}
+
+// Tags of the definition
+func (d *Definition) Tags() []Tag {
+	return d.tags
+}
 
+func createFromConstructorFunction(fn reflect.Value) (def Interface, err error) {
 	def = &Definition{
 		arguments:   make([]argument.Interface, 0),
 		methodCalls: make([]*method.Method, 0),
-		constructor: constructor,
-		t:           reflect.TypeOf(constructor.Interface()).Out(0),
+		constructor: fn,
+		t:           fn.Type().Out(0),
 	}
 
 	return
@@ -94,6 +169,7 @@ func createFromPointer(ptr interface{}, args ...interface{}) (def Interface, err
 	def = &Definition{
 		arguments:   make([]argument.Interface, 0),
 		methodCalls: make([]*method.Method, 0),
+		template:    reflect.ValueOf(ptr),
 		t:           reflect.TypeOf(ptr),
 	}
 
@@ -31,6 +31,38 @@ func TestNewWithInvalidConstructorFunction(t *testing.T) {
 	})
 }
 
+func TestNewWithConstructorFunctionReturningTooManyValues(t *testing.T) {
+	Convey("Given a constructor function with a second return value that is not an error", t, func() {
+		type Foo struct{}
+		_, err := New(func() (*Foo, int) { return &Foo{}, 0 })
+
+		Convey("Then there should be an error", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "Constructor function must return a single value and an optional error")
+		})
+	})
+}
+
+func TestNewWithConstructorFunctionReturningAnError(t *testing.T) {
+	Convey("Given a constructor function returning a value and an error", t, func() {
+		type Foo struct{}
+
+		Convey("When that function is used to create a definition", func() {
+			def, err := New(func() (*Foo, error) {
+				return &Foo{}, nil
+			})
+
+			Convey("Then it should return an empty error", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("And the definition should represent the first return type", func() {
+				So(def.Type(), ShouldEqual, reflect.TypeOf(&Foo{}))
+			})
+		})
+	})
+}
+
 func TestNewWithConstructorFunction(t *testing.T) {
 	Convey("Given a constructor function for an arbitraty type", t, func() {
 		type Foo struct{}
@@ -55,6 +87,29 @@ func TestNewWithConstructorFunction(t *testing.T) {
 	})
 }
 
+func TestNewWithConstructorFunctionKeepsTheFunction(t *testing.T) {
+	Convey("Given a constructor function for an arbitraty type", t, func() {
+		type Foo struct{ Called bool }
+		NewFoo := func() *Foo {
+			return &Foo{Called: true}
+		}
+
+		Convey("When that function is used to create a definition", func() {
+			def, err := New(NewFoo)
+
+			Convey("Then it should return an empty error", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("And calling the definition constructor should run the original function", func() {
+				ret := def.Constructor().Call(nil)[0].Interface()
+				So(ret.(*Foo).Called, ShouldBeTrue)
+				So(def.Type(), ShouldEqual, reflect.TypeOf(&Foo{}))
+			})
+		})
+	})
+}
+
 func TestNewWithInstance(t *testing.T) {
 	Convey("Given an instance of an arbitrary type", t, func() {
 		type Foo struct{}
@@ -72,6 +127,7 @@ func TestNewWithInstance(t *testing.T) {
 				So(def, ShouldHaveSameTypeAs, &Definition{})
 				So(def.Arguments(), ShouldHaveLength, 0)
 				So(def.Type(), ShouldHaveSameTypeAs, reflect.TypeOf(&Foo{}))
+				So(def.Type(), ShouldEqual, reflect.TypeOf(foo))
 				So(def.Constructor(), ShouldHaveSameTypeAs, reflect.Value{})
 			})
 		})
@@ -137,3 +193,56 @@ func TestAddMethodCall(t *testing.T) {
 		})
 	})
 }
+
+func TestSetScope(t *testing.T) {
+	Convey("Given a definition of an arbitrary type", t, func() {
+		def, _ := New(&Foo{})
+
+		Convey("Then it should be shared by default", func() {
+			So(def.Scope(), ShouldEqual, Shared)
+		})
+
+		Convey("And when its scope is set to prototype", func() {
+			def.SetScope(Prototype)
+
+			Convey("Then the definition should be a prototype", func() {
+				So(def.Scope(), ShouldEqual, Prototype)
+				So(def.Scope().String(), ShouldEqual, "prototype")
+			})
+		})
+	})
+}
+
+func TestParseScope(t *testing.T) {
+	Convey("Given the name of a scope", t, func() {
+		scope, err := ParseScope("scoped")
+
+		Convey("Then the scope should be returned", func() {
+			So(err, ShouldBeNil)
+			So(scope, ShouldEqual, Scoped)
+		})
+	})
+
+	Convey("Given an unknown scope name", t, func() {
+		_, err := ParseScope("request")
+
+		Convey("Then there should be an error", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Unknown scope "request"`)
+		})
+	})
+}
+
+func TestAddTag(t *testing.T) {
+	Convey("Given a definition of an arbitrary type", t, func() {
+		def, _ := New(&Foo{})
+
+		Convey("When a tag is added to it", func() {
+			def.AddTag("route", map[string]interface{}{"path": "/users"})
+
+			Convey("Then the definition should carry that tag", func() {
+				So(def.Tags(), ShouldResemble, []Tag{{Name: "route", Attributes: map[string]interface{}{"path": "/users"}}})
+			})
+		})
+	})
+}
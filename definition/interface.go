@@ -11,9 +11,18 @@ import (
 type Interface interface {
 	AddArguments(arg ...argument.Interface) Interface
 	AddMethodCall(method *method.Method) Interface
+	SetScope(scope Scope) Interface
+	OnStart(hook Hook) Interface
+	OnStop(hook Hook) Interface
+	AddTag(name string, attributes map[string]interface{}) Interface
 
 	Arguments() []argument.Interface
 	MethodCalls() []*method.Method
 	Constructor() reflect.Value
+	Template() reflect.Value
 	Type() reflect.Type
+	Scope() Scope
+	StartHooks() []Hook
+	StopHooks() []Hook
+	Tags() []Tag
 }
@@ -0,0 +1,41 @@
+package definition
+
+import "fmt"
+
+// Scope defines the lifetime of the instances of a service
+type Scope int
+
+const (
+	// Shared services are built once and reused for every request
+	Shared Scope = iota
+	// Prototype services are built again for every request
+	Prototype
+	// Scoped services are built once per scope
+	Scoped
+)
+
+// String representation of the scope
+func (s Scope) String() string {
+	switch s {
+	case Shared:
+		return "shared"
+	case Prototype:
+		return "prototype"
+	case Scoped:
+		return "scoped"
+	}
+
+	return "unknown"
+}
+
+// ParseScope returns the scope named s
+func ParseScope(s string) (scope Scope, err error) {
+	for _, scope = range []Scope{Shared, Prototype, Scoped} {
+		if scope.String() == s {
+			return
+		}
+	}
+
+	err = fmt.Errorf(`Unknown scope "%s"`, s)
+	return
+}
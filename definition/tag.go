@@ -0,0 +1,7 @@
+package definition
+
+// Tag of a service definition, with optional attributes
+type Tag struct {
+	Name       string
+	Attributes map[string]interface{}
+}
@@ -0,0 +1,17 @@
+module github.com/drgomesp/cargo
+
+go 1.24.0
+
+require (
+	github.com/smartystreets/goconvey v1.8.1
+	golang.org/x/tools v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gopherjs/gopherjs v1.17.2 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/smarty/assertions v1.15.0 // indirect
+	golang.org/x/mod v0.29.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
+)
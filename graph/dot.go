@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var dotStyles = map[string]string{
+	"inherited": `style=dashed`,
+	"unused":    `style=filled, fillcolor="#eeeeee", color="#999999"`,
+	"prototype": `style=filled, fillcolor="#dbeafe"`,
+	"scoped":    `style=filled, fillcolor="#fef3c7"`,
+}
+
+// DOT writes the dependency graph of src in the Graphviz DOT language
+func DOT(w io.Writer, src Source, opts ...Option) error {
+	d, err := build(src, opts)
+
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "digraph services {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+	fmt.Fprintln(b, "\tnode [shape=box];")
+
+	for _, n := range d.nodes {
+		attrs := "label=" + strconv.Quote(strings.Join(d.label(n), "\n"))
+
+		if style, ok := dotStyles[d.class(n)]; ok {
+			attrs += ", " + style
+		}
+
+		fmt.Fprintf(b, "\t%s [%s];\n", strconv.Quote(n.ID), attrs)
+
+		for _, alias := range n.Aliases {
+			fmt.Fprintf(b, "\t%s [shape=ellipse, style=dashed];\n", strconv.Quote(alias))
+			fmt.Fprintf(b, "\t%s -> %s [style=dashed];\n", strconv.Quote(alias), strconv.Quote(n.ID))
+		}
+	}
+
+	for _, n := range d.nodes {
+		for _, dep := range n.Dependencies {
+			fmt.Fprintf(b, "\t%s -> %s;\n", strconv.Quote(n.ID), strconv.Quote(dep))
+		}
+	}
+
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
@@ -0,0 +1,177 @@
+// Package graph exports the dependency graph of a container as Graphviz DOT
+// or Mermaid diagrams
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Source of the nodes of a diagram, such as a container
+type Source interface {
+	Nodes() []container.Node
+}
+
+// Option configures an exported diagram
+type Option func(o *options)
+
+type options struct {
+	scopes bool
+	unused bool
+	root   string
+}
+
+// WithScopes highlights prototype and scoped services
+func WithScopes() Option {
+	return func(o *options) {
+		o.scopes = true
+	}
+}
+
+// WithUnused highlights the services no other service depends on
+func WithUnused() Option {
+	return func(o *options) {
+		o.unused = true
+	}
+}
+
+// WithRoot limits the diagram to the service id and the services it
+// depends on, directly or not
+func WithRoot(id string) Option {
+	return func(o *options) {
+		o.root = id
+	}
+}
+
+// diagram of the nodes of a container, with the options applied
+type diagram struct {
+	options
+	nodes []node
+	index map[string]int
+}
+
+type node struct {
+	container.Node
+	inherited bool
+	unused    bool
+}
+
+// build the diagram of the nodes of src
+func build(src Source, opts []Option) (d *diagram, err error) {
+	d = &diagram{index: make(map[string]int)}
+
+	for _, opt := range opts {
+		opt(&d.options)
+	}
+
+	for _, n := range src.Nodes() {
+		d.add(node{Node: n})
+	}
+
+	// Services of parent containers, or missing ones, are only drawn as the
+	// target of edges
+	for i := 0; i < len(d.nodes); i++ {
+		for _, dep := range d.nodes[i].Dependencies {
+			if _, ok := d.index[dep]; !ok {
+				d.add(node{Node: container.Node{ID: dep}, inherited: true})
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+
+	for _, n := range d.nodes {
+		for _, dep := range n.Dependencies {
+			used[dep] = true
+		}
+	}
+
+	for i := range d.nodes {
+		d.nodes[i].unused = !used[d.nodes[i].ID] && len(d.nodes[i].Aliases) == 0
+	}
+
+	if d.root == "" {
+		return
+	}
+
+	if i, ok := d.index[d.root]; !ok || d.nodes[i].inherited {
+		return nil, fmt.Errorf(`No service "%s" was found`, d.root)
+	}
+
+	reachable := make(map[string]bool)
+	d.walk(d.root, reachable)
+
+	nodes := d.nodes
+	d.nodes, d.index = nil, make(map[string]int)
+
+	for _, n := range nodes {
+		if reachable[n.ID] {
+			d.add(n)
+		}
+	}
+
+	return
+}
+
+func (d *diagram) add(n node) {
+	d.index[n.ID] = len(d.nodes)
+	d.nodes = append(d.nodes, n)
+}
+
+func (d *diagram) walk(id string, reachable map[string]bool) {
+	if reachable[id] {
+		return
+	}
+
+	reachable[id] = true
+
+	for _, dep := range d.nodes[d.index[id]].Dependencies {
+		d.walk(dep, reachable)
+	}
+}
+
+// label lines of a node: its identifier, type, scope and tags
+func (d *diagram) label(n node) []string {
+	lines := []string{n.ID}
+
+	if n.Type != nil {
+		lines = append(lines, n.Type.String())
+	}
+
+	if d.scopes && n.Scope != definition.Shared {
+		lines = append(lines, "<<"+n.Scope.String()+">>")
+	}
+
+	if len(n.Tags) > 0 {
+		tags := make([]string, 0, len(n.Tags))
+
+		for _, tag := range n.Tags {
+			tags = append(tags, "#"+tag.Name)
+		}
+
+		sort.Strings(tags)
+		lines = append(lines, strings.Join(tags, " "))
+	}
+
+	return lines
+}
+
+// class of a node used to highlight it, if any
+func (d *diagram) class(n node) string {
+	switch {
+	case n.inherited:
+		return "inherited"
+	case d.unused && n.unused:
+		return "unused"
+	case d.scopes && n.Scope == definition.Prototype:
+		return "prototype"
+	case d.scopes && n.Scope == definition.Scoped:
+		return "scoped"
+	}
+
+	return ""
+}
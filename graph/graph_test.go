@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/reference"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Logger struct{}
+
+type DB struct{ Logger *Logger }
+
+type Handler struct{ DB *DB }
+
+type Request struct{}
+
+func newContainer() *container.Container {
+	c := container.New()
+	c.Register("logger", &Logger{})
+
+	db, _ := c.Register("db", func(logger *Logger) *DB { return &DB{logger} })
+	db.AddTag("health", nil)
+	c.Alias("database", "db")
+
+	handler, _ := c.Register("handler", func(db *DB) *Handler { return &Handler{db} })
+	handler.AddArguments(reference.New("database"))
+
+	request, _ := c.Register("request", &Request{})
+	request.SetScope(definition.Scoped)
+
+	return c
+}
+
+func TestDOT(t *testing.T) {
+	Convey("Given a container", t, func() {
+		c := newContainer()
+
+		Convey("When exporting it to DOT", func() {
+			var b bytes.Buffer
+			err := DOT(&b, c)
+
+			Convey("Then every service, alias and dependency should be drawn", func() {
+				So(err, ShouldBeNil)
+				So(b.String(), ShouldEqual, `digraph services {
+	rankdir=LR;
+	node [shape=box];
+	"db" [label="db\n*graph.DB\n#health"];
+	"database" [shape=ellipse, style=dashed];
+	"database" -> "db" [style=dashed];
+	"handler" [label="handler\n*graph.Handler"];
+	"logger" [label="logger\n*graph.Logger"];
+	"request" [label="request\n*graph.Request"];
+	"db" -> "logger";
+	"handler" -> "db";
+}
+`)
+			})
+		})
+
+		Convey("When exporting it with scopes and unused services highlighted", func() {
+			var b bytes.Buffer
+			err := DOT(&b, c, WithScopes(), WithUnused())
+
+			Convey("Then they should be styled", func() {
+				So(err, ShouldBeNil)
+				So(b.String(), ShouldContainSubstring, `"handler" [label="handler\n*graph.Handler", style=filled, fillcolor="#eeeeee", color="#999999"];`)
+				So(b.String(), ShouldContainSubstring, `"request" [label="request\n*graph.Request\n<<scoped>>", style=filled, fillcolor="#eeeeee", color="#999999"];`)
+				So(b.String(), ShouldContainSubstring, `"logger" [label="logger\n*graph.Logger"];`)
+			})
+		})
+
+		Convey("When exporting the subgraph of a service", func() {
+			var b bytes.Buffer
+			err := DOT(&b, c, WithRoot("db"))
+
+			Convey("Then only the service and its dependencies should be drawn", func() {
+				So(err, ShouldBeNil)
+				So(b.String(), ShouldNotContainSubstring, "handler")
+				So(b.String(), ShouldNotContainSubstring, "request")
+				So(b.String(), ShouldContainSubstring, `"db" -> "logger";`)
+			})
+		})
+
+		Convey("When exporting the subgraph of a missing service", func() {
+			err := DOT(&bytes.Buffer{}, c, WithRoot("cache"))
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `No service "cache" was found`)
+			})
+		})
+	})
+
+	Convey("Given a child container", t, func() {
+		child := newContainer().NewChild()
+		child.Register("api", func(h *Handler) *Handler { return h })
+
+		Convey("When exporting it to DOT", func() {
+			var b bytes.Buffer
+			err := DOT(&b, child)
+
+			Convey("Then services of its parent should be drawn as dashed targets", func() {
+				So(err, ShouldBeNil)
+				So(b.String(), ShouldContainSubstring, `"handler" [label="handler", style=dashed];`)
+				So(b.String(), ShouldContainSubstring, `"api" -> "handler";`)
+			})
+		})
+	})
+}
+
+func TestMermaid(t *testing.T) {
+	Convey("Given a container", t, func() {
+		c := newContainer()
+
+		Convey("When exporting it to Mermaid with scopes highlighted", func() {
+			var b bytes.Buffer
+			err := Mermaid(&b, c, WithScopes())
+
+			Convey("Then every service, alias and dependency should be drawn", func() {
+				So(err, ShouldBeNil)
+				So(b.String(), ShouldEqual, `flowchart LR
+	n0["db<br/>*graph.DB<br/>#health"]
+	a0(["database"]) -.-> n0
+	n1["handler<br/>*graph.Handler"]
+	n2["logger<br/>*graph.Logger"]
+	n3["request<br/>*graph.Request<br/>#lt;#lt;scoped#gt;#gt;"]
+	n0 --> n2
+	n1 --> n0
+	classDef scoped fill:#fef3c7
+	class n3 scoped
+`)
+			})
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var mermaidStyles = []struct{ class, style string }{
+	{"inherited", "stroke-dasharray: 4 4"},
+	{"unused", "fill:#eeeeee,stroke:#999999"},
+	{"prototype", "fill:#dbeafe"},
+	{"scoped", "fill:#fef3c7"},
+}
+
+// Mermaid writes the dependency graph of src as a Mermaid flowchart
+func Mermaid(w io.Writer, src Source, opts ...Option) error {
+	d, err := build(src, opts)
+
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	classes := make(map[string][]string)
+	aliases := 0
+
+	fmt.Fprintln(b, "flowchart LR")
+
+	for i, n := range d.nodes {
+		fmt.Fprintf(b, "\tn%d[\"%s\"]\n", i, mermaidLabel(d.label(n)))
+
+		if class := d.class(n); class != "" {
+			classes[class] = append(classes[class], fmt.Sprintf("n%d", i))
+		}
+
+		for _, alias := range n.Aliases {
+			fmt.Fprintf(b, "\ta%d([\"%s\"]) -.-> n%d\n", aliases, mermaidLabel([]string{alias}), i)
+			aliases++
+		}
+	}
+
+	for i, n := range d.nodes {
+		for _, dep := range n.Dependencies {
+			fmt.Fprintf(b, "\tn%d --> n%d\n", i, d.index[dep])
+		}
+	}
+
+	for _, style := range mermaidStyles {
+		if ids, ok := classes[style.class]; ok {
+			fmt.Fprintf(b, "\tclassDef %s %s\n", style.class, style.style)
+			fmt.Fprintf(b, "\tclass %s %s\n", strings.Join(ids, ","), style.class)
+		}
+	}
+
+	return b.Flush()
+}
+
+// mermaidLabel joins lines into a quoted Mermaid label
+func mermaidLabel(lines []string) string {
+	escaped := make([]string, len(lines))
+
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "&", "#amp;")
+		line = strings.ReplaceAll(line, `"`, "#quot;")
+		line = strings.ReplaceAll(line, "<", "#lt;")
+		escaped[i] = strings.ReplaceAll(line, ">", "#gt;")
+	}
+
+	return strings.Join(escaped, "<br/>")
+}
@@ -0,0 +1,24 @@
+package loader
+
+import "fmt"
+
+// Error found at a line of a configuration file
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error message prefixed with the location of the error
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the error found at that location
+func (e *Error) Unwrap() error {
+	return e.Err
+}
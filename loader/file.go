@@ -0,0 +1,327 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/drgomesp/cargo/argument"
+	"github.com/drgomesp/cargo/parameter"
+	"github.com/drgomesp/cargo/reference"
+	"github.com/drgomesp/cargo/tagged"
+	"gopkg.in/yaml.v3"
+)
+
+// File of service definitions, parsed from YAML or JSON:
+//
+//	parameters:
+//	  db.host: localhost
+//	services:
+//	  db:
+//	    constructor: NewDB
+//	    arguments: ["postgres://%db.host%", "@logger", ~]
+//	    calls:
+//	      - method: SetLogger
+//	        arguments: ["@logger"]
+//	    tags:
+//	      - name: health
+//	        priority: 10
+//	    scope: shared
+//
+// Arguments starting with @ refer to services, @@ escaping a leading @, and
+// strings with %name% placeholders refer to parameters. A null argument is
+// autowired by type, and a "!tagged name" argument collects every service
+// tagged with name.
+type File struct {
+	Name       string
+	Parameters []Parameter
+	Services   []Service
+}
+
+// Parameter of a configuration file
+type Parameter struct {
+	Name  string
+	Value interface{}
+	Line  int
+}
+
+// Service of a configuration file
+type Service struct {
+	ID          string
+	Constructor string
+	Arguments   []argument.Interface
+	Calls       []Call
+	Tags        []Tag
+	Scope       string
+	Line        int
+}
+
+// Call of a method of a service
+type Call struct {
+	Method    string
+	Arguments []argument.Interface
+	Line      int
+}
+
+// Tag of a service
+type Tag struct {
+	Name       string
+	Attributes map[string]interface{}
+	Line       int
+}
+
+// Parse a configuration file read from r, where name is used to locate errors
+func Parse(name string, r io.Reader) (file *File, err error) {
+	file = &File{Name: name}
+
+	var root yaml.Node
+
+	if err = yaml.NewDecoder(r).Decode(&root); err != nil {
+		if err == io.EOF {
+			err = nil
+		} else {
+			err = fmt.Errorf("%s: %s", name, err)
+		}
+
+		return
+	}
+
+	p := &parser{file: file}
+	doc := root.Content[0]
+
+	if doc.Kind != yaml.MappingNode {
+		p.fail(doc, "A configuration file must be a mapping")
+		return file, errors.Join(p.errs...)
+	}
+
+	p.each(doc, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "parameters":
+			p.parameters(value)
+		case "services":
+			p.services(value)
+		default:
+			p.fail(key, `Unknown section "%s"`, key.Value)
+		}
+	})
+
+	return file, errors.Join(p.errs...)
+}
+
+type parser struct {
+	file *File
+	errs []error
+}
+
+func (p *parser) fail(node *yaml.Node, format string, args ...interface{}) {
+	p.errs = append(p.errs, &Error{File: p.file.Name, Line: node.Line, Err: fmt.Errorf(format, args...)})
+}
+
+// each key and value of a mapping node
+func (p *parser) each(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], node.Content[i+1])
+	}
+}
+
+func (p *parser) parameters(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		p.fail(node, "Parameters must be a mapping")
+		return
+	}
+
+	p.each(node, func(key, value *yaml.Node) {
+		var decoded interface{}
+
+		if err := value.Decode(&decoded); err != nil {
+			p.fail(value, `Parameter "%s": %s`, key.Value, err)
+			return
+		}
+
+		p.file.Parameters = append(p.file.Parameters, Parameter{Name: key.Value, Value: decoded, Line: key.Line})
+	})
+}
+
+func (p *parser) services(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		p.fail(node, "Services must be a mapping")
+		return
+	}
+
+	p.each(node, func(key, value *yaml.Node) {
+		service := Service{ID: key.Value, Line: key.Line}
+
+		if value.Kind != yaml.MappingNode {
+			p.fail(value, `Service "%s" must be a mapping`, service.ID)
+			return
+		}
+
+		p.each(value, func(key, value *yaml.Node) {
+			switch key.Value {
+			case "constructor":
+				service.Constructor = p.string(service.ID, key, value)
+			case "arguments":
+				service.Arguments = p.arguments(service.ID, value)
+			case "calls":
+				service.Calls = p.calls(service.ID, value)
+			case "tags":
+				service.Tags = p.tags(service.ID, value)
+			case "scope":
+				service.Scope = p.string(service.ID, key, value)
+			default:
+				p.fail(key, `Service "%s": Unknown key "%s"`, service.ID, key.Value)
+			}
+		})
+
+		if service.Constructor == "" {
+			p.fail(key, `Service "%s" has no constructor`, service.ID)
+			return
+		}
+
+		p.file.Services = append(p.file.Services, service)
+	})
+}
+
+func (p *parser) string(id string, key, value *yaml.Node) string {
+	if value.Kind != yaml.ScalarNode || value.Tag != "!!str" {
+		p.fail(value, `Service "%s": "%s" must be a string`, id, key.Value)
+		return ""
+	}
+
+	return value.Value
+}
+
+func (p *parser) arguments(id string, node *yaml.Node) (args []argument.Interface) {
+	if node.Kind != yaml.SequenceNode {
+		p.fail(node, `Service "%s": Arguments must be a sequence`, id)
+		return
+	}
+
+	for _, value := range node.Content {
+		arg, err := parseArgument(value)
+
+		if err != nil {
+			p.fail(value, `Service "%s": %s`, id, err)
+			continue
+		}
+
+		args = append(args, arg)
+	}
+
+	return
+}
+
+func (p *parser) calls(id string, node *yaml.Node) (calls []Call) {
+	if node.Kind != yaml.SequenceNode {
+		p.fail(node, `Service "%s": Calls must be a sequence`, id)
+		return
+	}
+
+	for _, value := range node.Content {
+		call := Call{Line: value.Line}
+
+		if value.Kind != yaml.MappingNode {
+			p.fail(value, `Service "%s": A call must be a mapping`, id)
+			continue
+		}
+
+		p.each(value, func(key, value *yaml.Node) {
+			switch key.Value {
+			case "method":
+				call.Method = p.string(id, key, value)
+			case "arguments":
+				call.Arguments = p.arguments(id, value)
+			default:
+				p.fail(key, `Service "%s": Unknown key "%s"`, id, key.Value)
+			}
+		})
+
+		if call.Method == "" {
+			p.fail(value, `Service "%s": A call must have a method`, id)
+			continue
+		}
+
+		calls = append(calls, call)
+	}
+
+	return
+}
+
+func (p *parser) tags(id string, node *yaml.Node) (tags []Tag) {
+	if node.Kind != yaml.SequenceNode {
+		p.fail(node, `Service "%s": Tags must be a sequence`, id)
+		return
+	}
+
+	for _, value := range node.Content {
+		tag := Tag{Line: value.Line}
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			tag.Name = value.Value
+		case yaml.MappingNode:
+			tag.Attributes = make(map[string]interface{})
+
+			p.each(value, func(key, value *yaml.Node) {
+				if key.Value == "name" {
+					tag.Name = p.string(id, key, value)
+					return
+				}
+
+				var decoded interface{}
+
+				if err := value.Decode(&decoded); err != nil {
+					p.fail(value, `Service "%s": %s`, id, err)
+					return
+				}
+
+				tag.Attributes[key.Value] = decoded
+			})
+		}
+
+		if tag.Name == "" {
+			p.fail(value, `Service "%s": A tag must have a name`, id)
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return
+}
+
+// parseArgument converts a node into a service reference, a parameter or a
+// literal argument
+func parseArgument(node *yaml.Node) (arg argument.Interface, err error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!tagged" {
+		return tagged.New(node.Value), nil
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		value := node.Value
+
+		switch {
+		case strings.HasPrefix(value, "@@"):
+			return argument.New(value[1:]), nil
+		case strings.HasPrefix(value, "@"):
+			return reference.New(value[1:]), nil
+		case strings.ContainsRune(value, '%'):
+			return parameter.New(value), nil
+		}
+
+		return argument.New(value), nil
+	}
+
+	var decoded interface{}
+
+	if err = node.Decode(&decoded); err != nil {
+		return
+	}
+
+	return argument.New(decoded), nil
+}
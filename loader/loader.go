@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	"github.com/drgomesp/cargo/method"
+)
+
+// Loader registers the services of configuration files on a container,
+// building them with the constructors of a registry
+type Loader struct {
+	registry *Registry
+}
+
+// New creates a loader of configuration files whose constructors are found
+// in registry
+func New(registry *Registry) *Loader {
+	return &Loader{
+		registry: registry,
+	}
+}
+
+// LoadFile parses the YAML or JSON file at path and registers its
+// parameters and services on c
+func (l *Loader) LoadFile(c *container.Container, path string) (err error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	return l.Load(c, path, f)
+}
+
+// Load parses a configuration file read from r and registers its parameters
+// and services on c, where name is used to locate errors. The valid entries
+// are registered even when others are invalid, so that all errors are
+// reported at once.
+func (l *Loader) Load(c *container.Container, name string, r io.Reader) error {
+	file, err := Parse(name, r)
+
+	return errors.Join(err, l.Apply(c, file))
+}
+
+// Apply registers the parameters and services of a parsed file on c. Every
+// invalid entry is reported, each located by its line.
+func (l *Loader) Apply(c *container.Container, file *File) error {
+	var errs []error
+
+	fail := func(line int, err error) {
+		errs = append(errs, &Error{File: file.Name, Line: line, Err: err})
+	}
+
+	for _, param := range file.Parameters {
+		if err := c.SetParameter(param.Name, param.Value); err != nil {
+			fail(param.Line, err)
+		}
+	}
+
+	for _, service := range file.Services {
+		constructor, ok := l.registry.Lookup(service.Constructor)
+
+		if !ok {
+			fail(service.Line, fmt.Errorf(`Service "%s": Unknown constructor "%s"`, service.ID, service.Constructor))
+			continue
+		}
+
+		scope := definition.Shared
+
+		if service.Scope != "" {
+			var err error
+
+			if scope, err = definition.ParseScope(service.Scope); err != nil {
+				fail(service.Line, fmt.Errorf(`Service "%s": %s`, service.ID, err))
+				continue
+			}
+		}
+
+		def, err := c.Register(service.ID, constructor)
+
+		if err != nil {
+			fail(service.Line, err)
+			continue
+		}
+
+		def.AddArguments(service.Arguments...)
+		def.SetScope(scope)
+
+		for _, call := range service.Calls {
+			def.AddMethodCall(&method.Method{Name: call.Method, Args: call.Arguments})
+		}
+
+		for _, tag := range service.Tags {
+			def.AddTag(tag.Name, tag.Attributes)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package loader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drgomesp/cargo/container"
+	"github.com/drgomesp/cargo/definition"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Logger struct {
+	Prefix string
+}
+
+func NewLogger(prefix string) *Logger {
+	return &Logger{prefix}
+}
+
+type Database struct {
+	DSN    string
+	Pool   int64
+	Logger *Logger
+}
+
+func NewDatabase(dsn string, pool int64, logger *Logger) *Database {
+	return &Database{DSN: dsn, Pool: pool, Logger: logger}
+}
+
+func (d *Database) SetLogger(logger *Logger) {
+	d.Logger = logger
+}
+
+type Request struct {
+	Size uint32
+}
+
+func NewRequest(size uint32) *Request {
+	return &Request{size}
+}
+
+type Checks struct {
+	Services []interface{}
+}
+
+func NewChecks(services []interface{}) *Checks {
+	return &Checks{services}
+}
+
+const services = `
+parameters:
+  db.host: localhost
+  db.pool: 10
+services:
+  logger:
+    constructor: NewLogger
+    arguments: ["@@app"]
+  db:
+    constructor: NewDatabase
+    arguments: ["postgres://%db.host%", "%db.pool%", ~]
+    calls:
+      - method: SetLogger
+        arguments: ["@logger"]
+    tags:
+      - health
+      - name: metrics
+        priority: 10
+  checks:
+    constructor: NewChecks
+    arguments: [!tagged health]
+  request:
+    constructor: NewRequest
+    arguments: [1024]
+    scope: prototype
+`
+
+func newLoader() *Loader {
+	registry := NewRegistry()
+	registry.Register("NewLogger", NewLogger)
+	registry.Register("NewDatabase", NewDatabase)
+	registry.Register("NewRequest", NewRequest)
+	registry.Register("NewChecks", NewChecks)
+
+	return New(registry)
+}
+
+func TestRegistry(t *testing.T) {
+	Convey("Given a registry", t, func() {
+		registry := NewRegistry()
+
+		Convey("When registering a constructor", func() {
+			err := registry.Register("NewLogger", NewLogger)
+
+			Convey("Then it should be found by its name", func() {
+				So(err, ShouldBeNil)
+
+				constructor, ok := registry.Lookup("NewLogger")
+				So(ok, ShouldBeTrue)
+				So(constructor.(func(string) *Logger)("app").Prefix, ShouldEqual, "app")
+				So(registry.Names(), ShouldResemble, []string{"NewLogger"})
+			})
+		})
+
+		Convey("When registering a name twice", func() {
+			registry.Register("NewLogger", NewLogger)
+			err := registry.Register("NewLogger", NewLogger)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `Constructor "NewLogger" already exists`)
+			})
+		})
+
+		Convey("When registering something that is not a constructor", func() {
+			err := registry.Register("Logger", 10)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given a loader and a YAML configuration", t, func() {
+		c := container.New()
+		err := newLoader().Load(c, "services.yml", strings.NewReader(services))
+
+		So(err, ShouldBeNil)
+
+		Convey("Then its parameters should be set", func() {
+			host, err := c.GetParameter("db.host")
+
+			So(err, ShouldBeNil)
+			So(host, ShouldEqual, "localhost")
+		})
+
+		Convey("Then its services should be built with their arguments and calls", func() {
+			service, err := c.Get("db")
+
+			So(err, ShouldBeNil)
+
+			db := service.(*Database)
+			So(db.DSN, ShouldEqual, "postgres://localhost")
+			So(db.Pool, ShouldEqual, 10)
+			So(db.Logger, ShouldEqual, c.MustGet("logger"))
+			So(db.Logger.Prefix, ShouldEqual, "@app")
+		})
+
+		Convey("Then the tags and scopes of its services should be defined", func() {
+			db, _ := c.Definition("db")
+			So(db.Tags(), ShouldResemble, []definition.Tag{
+				{Name: "health"},
+				{Name: "metrics", Attributes: map[string]interface{}{"priority": 10}},
+			})
+
+			checks, err := c.Get("checks")
+			So(err, ShouldBeNil)
+			So(checks.(*Checks).Services, ShouldResemble, []interface{}{c.MustGet("db")})
+
+			request, _ := c.Definition("request")
+			So(request.Scope(), ShouldEqual, definition.Prototype)
+			So(c.MustGet("request"), ShouldNotPointTo, c.MustGet("request"))
+		})
+	})
+
+	Convey("Given a loader and a JSON configuration", t, func() {
+		c := container.New()
+		err := newLoader().Load(c, "services.json", strings.NewReader(`{
+			"services": {
+				"logger": {"constructor": "NewLogger", "arguments": ["app"]}
+			}
+		}`))
+
+		So(err, ShouldBeNil)
+
+		Convey("Then its services should be registered", func() {
+			logger, err := c.Get("logger")
+
+			So(err, ShouldBeNil)
+			So(logger.(*Logger).Prefix, ShouldEqual, "app")
+		})
+	})
+
+	Convey("Given a loader and a configuration with invalid entries", t, func() {
+		c := container.New()
+		err := newLoader().Load(c, "services.yml", strings.NewReader(`
+services:
+  logger:
+    constructor: NewLogger
+    argument: [app]
+  db:
+    constructor: OpenDatabase
+  cache:
+    constructor: NewLogger
+    scope: session
+`))
+
+		Convey("Then every error should be returned with its line", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, strings.Join([]string{
+				`services.yml:5: Service "logger": Unknown key "argument"`,
+				`services.yml:6: Service "db": Unknown constructor "OpenDatabase"`,
+				`services.yml:8: Service "cache": Unknown scope "session"`,
+			}, "\n"))
+
+			var located *Error
+			So(errors.As(err, &located), ShouldBeTrue)
+			So(located.Line, ShouldEqual, 5)
+		})
+	})
+}
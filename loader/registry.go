@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/drgomesp/cargo/definition"
+)
+
+// Registry of the constructor functions that configuration files refer to
+// by name
+type Registry struct {
+	mu           sync.RWMutex
+	constructors map[string]interface{}
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		constructors: make(map[string]interface{}, 0),
+	}
+}
+
+// Register a constructor function, or a struct pointer to inject fields
+// into, under a name
+func (r *Registry) Register(name string, constructor interface{}) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.constructors[name]; ok {
+		err = fmt.Errorf(`Constructor "%s" already exists`, name)
+		return
+	}
+
+	if _, err = definition.New(constructor); err != nil {
+		return
+	}
+
+	r.constructors[name] = constructor
+	return
+}
+
+// Lookup the constructor registered under a name
+func (r *Registry) Lookup(name string) (constructor interface{}, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	constructor, ok = r.constructors[name]
+	return
+}
+
+// Names of every registered constructor in lexical order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.constructors))
+
+	for name := range r.constructors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
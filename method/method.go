@@ -5,14 +5,21 @@ import "github.com/drgomesp/cargo/argument"
 // Method represents a method for a service definition
 type Method struct {
 	Name string
-	Args []*argument.Argument
+	Args []argument.Interface
 }
 
-// New method reference
+// New method reference. Arguments implementing argument.Interface, such as
+// service references, are kept as they are and resolved by the container;
+// any other value is passed as a literal argument.
 func New(name string, args ...interface{}) *Method {
-	arguments := make([]*argument.Argument, len(args))
+	arguments := make([]argument.Interface, len(args))
 
 	for i, arg := range args {
+		if arg, ok := arg.(argument.Interface); ok {
+			arguments[i] = arg
+			continue
+		}
+
 		arguments[i] = argument.New(arg)
 	}
 
@@ -0,0 +1,123 @@
+package parameter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Variable of an env placeholder, such as env(PORT) or env(int:PORT=8080),
+// resolved from an environment variable converted to a type
+type Variable struct {
+	// Name of the environment variable
+	Name string
+	// Type among string, int, float, bool and duration
+	Type string
+	// Default value used when the variable is not set, if HasDefault
+	Default    string
+	HasDefault bool
+}
+
+// Env parameter argument resolved from an environment variable when the
+// service is built. The spec names the variable, optionally preceded by a
+// type among string, int, float, bool and duration, and followed by a
+// default value, as in Env("int:PORT=8080").
+func Env(spec string) *Parameter {
+	return New("%env(" + strings.ReplaceAll(spec, "%", "%%") + ")%")
+}
+
+// parseEnv parses the text of a placeholder when it is an env placeholder
+func parseEnv(text string) (e Variable, ok bool) {
+	if !strings.HasPrefix(text, "env(") || !strings.HasSuffix(text, ")") {
+		return
+	}
+
+	spec := text[len("env(") : len(text)-1]
+	e.Type = "string"
+
+	if i := strings.IndexByte(spec, '='); i >= 0 {
+		spec, e.Default, e.HasDefault = spec[:i], spec[i+1:], true
+	}
+
+	if i := strings.IndexByte(spec, ':'); i >= 0 {
+		e.Type, spec = spec[:i], spec[i+1:]
+	}
+
+	e.Name = spec
+	return e, true
+}
+
+// zeros are the text of the zero value of each type of env placeholder
+var zeros = map[string]string{
+	"string":   "",
+	"int":      "0",
+	"float":    "0",
+	"bool":     "false",
+	"duration": "0s",
+}
+
+// resolve the value of the environment variable, or its default value
+func (e Variable) resolve() (value interface{}, err error) {
+	raw, ok := os.LookupEnv(e.Name)
+
+	if !ok {
+		if !e.HasDefault {
+			err = fmt.Errorf(`Environment variable "%s" is not set`, e.Name)
+			return
+		}
+
+		raw = e.Default
+	}
+
+	return e.convert(raw)
+}
+
+// check that the type and the default value of the placeholder are valid,
+// without reading the environment variable, returning the default value or
+// the zero value of the type
+func (e Variable) check() (value interface{}, err error) {
+	if e.HasDefault {
+		return e.convert(e.Default)
+	}
+
+	return e.convert(zeros[e.Type])
+}
+
+// convert the text of a value to the type of the placeholder
+func (e Variable) convert(raw string) (value interface{}, err error) {
+	switch e.Type {
+	case "string":
+		value = raw
+	case "int":
+		value, err = strconv.Atoi(raw)
+	case "float":
+		value, err = strconv.ParseFloat(raw, 64)
+	case "bool":
+		value, err = strconv.ParseBool(raw)
+	case "duration":
+		value, err = time.ParseDuration(raw)
+	default:
+		err = fmt.Errorf(`Environment variable "%s" has an unknown type "%s"`, e.Name, e.Type)
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf(`Environment variable "%s" is not a valid %s: "%s"`, e.Name, e.Type, raw)
+	}
+
+	return
+}
+
+// withEnv resolves env placeholders with resolve and any other placeholder
+// with lookup
+func withEnv(lookup func(name string) (interface{}, error), resolve func(e Variable) (interface{}, error)) func(name string) (interface{}, error) {
+	return func(name string) (interface{}, error) {
+		if e, ok := parseEnv(name); ok {
+			return resolve(e)
+		}
+
+		return lookup(name)
+	}
+}
@@ -0,0 +1,12 @@
+package parameter
+
+import "github.com/drgomesp/cargo/argument"
+
+// Interface that defines an argument resolved from container parameters
+type Interface interface {
+	argument.Interface
+	Names() []string
+	Parts() ([]Part, error)
+	Resolve(lookup func(name string) (interface{}, error)) (interface{}, error)
+	Check(lookup func(name string) (interface{}, error)) (interface{}, error)
+}
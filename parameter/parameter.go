@@ -0,0 +1,151 @@
+package parameter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parameter argument referring to container parameters through %name%
+// placeholders, or to environment variables through %env(NAME)%
+// placeholders, where %% stands for a percent sign
+type Parameter struct {
+	value string
+}
+
+// Value carried by the argument
+func (p *Parameter) Value() interface{} {
+	return p.value
+}
+
+// Names of the container parameters referred to by the placeholders
+func (p *Parameter) Names() (names []string) {
+	parts, _ := parse(p.value)
+
+	for _, part := range parts {
+		if _, ok := parseEnv(part.text); part.placeholder && !ok {
+			names = append(names, part.text)
+		}
+	}
+
+	return
+}
+
+// Part of the value of a parameter argument
+type Part struct {
+	// Text is literal text, or the text of a placeholder
+	Text        string
+	Placeholder bool
+	// Env is the variable of an env placeholder, nil for other parts
+	Env *Variable
+}
+
+// Parts of the value, in order
+func (p *Parameter) Parts() (parts []Part, err error) {
+	found, err := parse(p.value)
+
+	for _, part := range found {
+		next := Part{Text: part.text, Placeholder: part.placeholder}
+
+		if e, ok := parseEnv(part.text); part.placeholder && ok {
+			next.Env = &e
+		}
+
+		parts = append(parts, next)
+	}
+
+	return
+}
+
+// Resolve the placeholders with the values returned by lookup. A value made
+// of a single placeholder resolves to the parameter itself, keeping its type,
+// while placeholders within a string are replaced by their string form.
+// Environment variables are read when resolving the parameter.
+func (p *Parameter) Resolve(lookup func(name string) (interface{}, error)) (value interface{}, err error) {
+	return p.resolve(withEnv(lookup, Variable.resolve))
+}
+
+// Check resolves the parameter without reading the environment, as when
+// validating a container ahead of building its services. Environment
+// variables are only checked to be well formed, and stand for their default
+// value or the zero value of their type.
+func (p *Parameter) Check(lookup func(name string) (interface{}, error)) (value interface{}, err error) {
+	return p.resolve(withEnv(lookup, Variable.check))
+}
+
+func (p *Parameter) resolve(lookup func(name string) (interface{}, error)) (value interface{}, err error) {
+	parts, err := parse(p.value)
+
+	if err != nil {
+		return
+	}
+
+	if len(parts) == 1 && parts[0].placeholder {
+		return lookup(parts[0].text)
+	}
+
+	var b strings.Builder
+
+	for _, part := range parts {
+		if !part.placeholder {
+			b.WriteString(part.text)
+			continue
+		}
+
+		if value, err = lookup(part.text); err != nil {
+			return
+		}
+
+		fmt.Fprint(&b, value)
+	}
+
+	return b.String(), nil
+}
+
+// New parameter argument, such as "%db.port%" or "postgres://%db.host%"
+func New(value string) *Parameter {
+	return &Parameter{value}
+}
+
+type part struct {
+	text        string
+	placeholder bool
+}
+
+// parse a value into literal text and placeholders
+func parse(value string) (parts []part, err error) {
+	var literal strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			literal.WriteByte(value[i])
+			continue
+		}
+
+		end := strings.IndexByte(value[i+1:], '%')
+
+		if end < 0 {
+			err = fmt.Errorf(`Unterminated placeholder in "%s"`, value)
+			return
+		}
+
+		if end == 0 {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+
+		if literal.Len() > 0 {
+			parts = append(parts, part{text: literal.String()})
+			literal.Reset()
+		}
+
+		parts = append(parts, part{text: value[i+1 : i+1+end], placeholder: true})
+		i += end + 1
+	}
+
+	if literal.Len() > 0 {
+		parts = append(parts, part{text: literal.String()})
+	}
+
+	return
+}
@@ -0,0 +1,193 @@
+package parameter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResolve(t *testing.T) {
+	parameters := map[string]interface{}{
+		"db.host": "localhost",
+		"db.port": 5432,
+	}
+
+	lookup := func(name string) (interface{}, error) {
+		if value, ok := parameters[name]; ok {
+			return value, nil
+		}
+
+		return nil, fmt.Errorf(`No parameter "%s" was found`, name)
+	}
+
+	Convey("Given a parameter made of a single placeholder", t, func() {
+		param := New("%db.port%")
+
+		Convey("Then it should resolve to the parameter value, keeping its type", func() {
+			value, err := param.Resolve(lookup)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 5432)
+			So(param.Names(), ShouldResemble, []string{"db.port"})
+		})
+	})
+
+	Convey("Given a parameter with placeholders within a string", t, func() {
+		param := New("postgres://%db.host%:%db.port%/app?sslmode=100%%")
+
+		Convey("Then it should resolve to the interpolated string", func() {
+			value, err := param.Resolve(lookup)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "postgres://localhost:5432/app?sslmode=100%")
+			So(param.Names(), ShouldResemble, []string{"db.host", "db.port"})
+		})
+	})
+
+	Convey("Given a parameter referring to an unknown parameter", t, func() {
+		param := New("%db.name%")
+
+		Convey("Then resolving it should return an error", func() {
+			_, err := param.Resolve(lookup)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `No parameter "db.name" was found`)
+		})
+	})
+
+	Convey("Given a parameter with an unterminated placeholder", t, func() {
+		param := New("postgres://%db.host")
+
+		Convey("Then resolving it should return an error", func() {
+			_, err := param.Resolve(lookup)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Unterminated placeholder in "postgres://%db.host"`)
+		})
+	})
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("CARGO_HOST", "localhost")
+	t.Setenv("CARGO_PORT", "8080")
+	t.Setenv("CARGO_TIMEOUT", "5s")
+	t.Setenv("CARGO_DEBUG", "yes")
+
+	lookup := func(name string) (interface{}, error) {
+		return nil, fmt.Errorf(`No parameter "%s" was found`, name)
+	}
+
+	Convey("Given a parameter referring to an environment variable", t, func() {
+		param := New("%env(CARGO_HOST)%")
+
+		Convey("Then it should resolve to the value of the variable", func() {
+			value, err := param.Resolve(lookup)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "localhost")
+			So(param.Names(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given typed environment parameters", t, func() {
+		Convey("Then they should resolve to values of their types", func() {
+			port, err := Env("int:CARGO_PORT").Resolve(lookup)
+			So(err, ShouldBeNil)
+			So(port, ShouldEqual, 8080)
+
+			timeout, err := Env("duration:CARGO_TIMEOUT").Resolve(lookup)
+			So(err, ShouldBeNil)
+			So(timeout, ShouldEqual, 5*time.Second)
+
+			ratio, err := Env("float:CARGO_RATIO=0.5").Resolve(lookup)
+			So(err, ShouldBeNil)
+			So(ratio, ShouldEqual, 0.5)
+		})
+	})
+
+	Convey("Given an environment variable within a string", t, func() {
+		param := New("http://%env(CARGO_HOST)%:%env(int:CARGO_PORT)%")
+
+		Convey("Then it should resolve to the interpolated string", func() {
+			value, err := param.Resolve(lookup)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "http://localhost:8080")
+		})
+	})
+
+	Convey("Given an unset environment variable with a default value", t, func() {
+		param := Env("bool:CARGO_VERBOSE=true")
+
+		Convey("Then it should resolve to the default value", func() {
+			value, err := param.Resolve(lookup)
+
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, true)
+		})
+	})
+
+	Convey("Given an unset environment variable without a default value", t, func() {
+		param := Env("CARGO_USER")
+
+		Convey("Then resolving it should return an error", func() {
+			_, err := param.Resolve(lookup)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Environment variable "CARGO_USER" is not set`)
+		})
+	})
+
+	Convey("Given an environment variable of the wrong type", t, func() {
+		param := Env("bool:CARGO_DEBUG")
+
+		Convey("Then resolving it should return an error", func() {
+			_, err := param.Resolve(lookup)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Environment variable "CARGO_DEBUG" is not a valid bool: "yes"`)
+		})
+	})
+
+	Convey("Given an environment variable of an unknown type", t, func() {
+		param := Env("url:CARGO_HOST")
+
+		Convey("Then resolving it should return an error", func() {
+			_, err := param.Resolve(lookup)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `Environment variable "CARGO_HOST" has an unknown type "url"`)
+		})
+	})
+
+	Convey("Given environment parameters checked ahead of building services", t, func() {
+		Convey("Then unset variables should stand for their default or zero value", func() {
+			user, err := Env("CARGO_USER").Check(lookup)
+			So(err, ShouldBeNil)
+			So(user, ShouldEqual, "")
+
+			retries, err := Env("int:CARGO_RETRIES").Check(lookup)
+			So(err, ShouldBeNil)
+			So(retries, ShouldEqual, 0)
+
+			verbose, err := Env("bool:CARGO_VERBOSE=true").Check(lookup)
+			So(err, ShouldBeNil)
+			So(verbose, ShouldEqual, true)
+		})
+
+		Convey("Then set variables should not be read", func() {
+			_, err := Env("bool:CARGO_DEBUG").Check(lookup)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then unknown types and invalid default values should be reported", func() {
+			_, err := Env("url:CARGO_HOST").Check(lookup)
+			So(err.Error(), ShouldEqual, `Environment variable "CARGO_HOST" has an unknown type "url"`)
+
+			_, err = Env("duration:CARGO_TIMEOUT=soon").Check(lookup)
+			So(err.Error(), ShouldEqual, `Environment variable "CARGO_TIMEOUT" is not a valid duration: "soon"`)
+		})
+	})
+}
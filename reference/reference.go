@@ -7,12 +7,12 @@ type Reference struct {
 }
 
 // Value carried by the argument
-func (r *Reference) Value() interface{} {
+func (r Reference) Value() interface{} {
 	return r.value
 }
 
 // Identifier of the referenced service
-func (r *Reference) Identifier() string {
+func (r Reference) Identifier() string {
 	return r.identifier
 }
 
@@ -13,5 +13,11 @@ func TestNewReference(t *testing.T) {
 			So(ref, ShouldHaveSameTypeAs, Reference{})
 			So(ref.Identifier(), ShouldEqual, "foo")
 		})
+
+		Convey("And that reference should be usable as an argument", func() {
+			var arg interface{} = ref
+			_, ok := arg.(Interface)
+			So(ok, ShouldBeTrue)
+		})
 	})
 }
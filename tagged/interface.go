@@ -0,0 +1,9 @@
+package tagged
+
+import "github.com/drgomesp/cargo/argument"
+
+// Interface that defines an argument collecting tagged services
+type Interface interface {
+	argument.Interface
+	Tag() string
+}
@@ -0,0 +1,25 @@
+package tagged
+
+// Tagged argument, injected as a slice or a map keyed by identifier of every
+// service with a tag, ordered by priority
+type Tagged struct {
+	value interface{}
+	tag   string
+}
+
+// Value carried by the argument
+func (t Tagged) Value() interface{} {
+	return t.value
+}
+
+// Tag of the collected services
+func (t Tagged) Tag() string {
+	return t.tag
+}
+
+// New argument collecting the services tagged with tag
+func New(tag string) Tagged {
+	return Tagged{
+		tag: tag,
+	}
+}
@@ -0,0 +1,23 @@
+package tagged
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewTagged(t *testing.T) {
+	Convey("Given a tagged argument is created with a tag", t, func() {
+		arg := New("health")
+		Convey("Then it should return an argument with that tag", func() {
+			So(arg, ShouldHaveSameTypeAs, Tagged{})
+			So(arg.Tag(), ShouldEqual, "health")
+		})
+
+		Convey("And that argument should be usable as an argument", func() {
+			var value interface{} = arg
+			_, ok := value.(Interface)
+			So(ok, ShouldBeTrue)
+		})
+	})
+}